@@ -1,68 +1,244 @@
 package config
 
 import (
+	"fmt"
+	"os"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
 	"github.com/spf13/viper"
 )
 
+var Version = "dev"
+
 type Config struct {
 	Telegram   TelegramConfig   `mapstructure:"telegram"`
 	MEXC       MEXCConfig       `mapstructure:"mexc"`
 	Monitoring MonitoringConfig `mapstructure:"monitoring"`
 	Database   DatabaseConfig   `mapstructure:"database"`
 	Logging    LoggingConfig    `mapstructure:"logging"`
+	Display    DisplayConfig    `mapstructure:"display"`
 }
 
 type TelegramConfig struct {
-	BotToken string `mapstructure:"bot_token"`
+	BotToken        string  `mapstructure:"bot_token"`
+	BotTokenFile    string  `mapstructure:"bot_token_file"`
+	Admins          []int64 `mapstructure:"admins"`
+	Mode            string  `mapstructure:"mode"`
+	WebhookURL      string  `mapstructure:"webhook_url"`
+	WebhookListen   string  `mapstructure:"webhook_listen"`
+	WebhookSecret   string  `mapstructure:"webhook_secret"`
+	SendConcurrency int     `mapstructure:"send_concurrency"`
+	CommandsPerMin  int     `mapstructure:"commands_per_minute"`
+	StartupSummary  bool    `mapstructure:"startup_summary"`
+	IncludeLink     bool    `mapstructure:"include_link"`
 }
 
 type MEXCConfig struct {
-	WebSocketURL string `mapstructure:"websocket_url"`
+	WebSocketURL  string            `mapstructure:"websocket_url"`
+	WebSocketURLs []string          `mapstructure:"websocket_urls"`
+	TLS           TLSConfig         `mapstructure:"tls"`
+	UserAgent     string            `mapstructure:"user_agent"`
+	Headers       map[string]string `mapstructure:"headers"`
+	Mock          bool              `mapstructure:"mock"`
+}
+
+type TLSConfig struct {
+	CAFile   string `mapstructure:"ca_file"`
+	CertFile string `mapstructure:"cert_file"`
+	KeyFile  string `mapstructure:"key_file"`
 }
 
 type MonitoringConfig struct {
-	TimeInterval int     `mapstructure:"time_interval"`
-	PriceChange  float64 `mapstructure:"price_change"`
-	MinVolume    int     `mapstructure:"min_volume"`
+	TimeInterval          int               `mapstructure:"time_interval"`
+	PriceChange           float64           `mapstructure:"price_change"`
+	MinVolume             int               `mapstructure:"min_volume"`
+	AlwaysSymbols         []string          `mapstructure:"always_symbols"`
+	IncludeOnly           []string          `mapstructure:"include_only"`
+	QuoteAssets           []string          `mapstructure:"quote_assets"`
+	DefaultBlacklist      []string          `mapstructure:"default_blacklist"`
+	StalenessThreshold    int               `mapstructure:"staleness_threshold"`
+	CompletedIntervals    bool              `mapstructure:"completed_intervals"`
+	DedupePrices          bool              `mapstructure:"dedupe_prices"`
+	PriceEpsilon          float64           `mapstructure:"price_epsilon"`
+	EMAAlpha              float64           `mapstructure:"ema_alpha"`
+	RoundToTick           bool              `mapstructure:"round_to_tick"`
+	DataTimeout           int               `mapstructure:"data_timeout"`
+	MissingVolume         string            `mapstructure:"missing_volume"`
+	AccelerationThreshold float64           `mapstructure:"acceleration_threshold"`
+	RelativeTo            string            `mapstructure:"relative_to"`
+	ChangeReference       string            `mapstructure:"change_reference"`
+	SessionOpen           string            `mapstructure:"session_open"`
+	GapThreshold          int               `mapstructure:"gap_threshold"`
+	DefaultSource         string            `mapstructure:"default_source"`
+	DataSources           map[string]string `mapstructure:"data_sources"`
+	AnalysisWorkers       int               `mapstructure:"analysis_workers"`
+	NotifySlowAnalysis    bool              `mapstructure:"notify_slow_analysis"`
+	AlertCooldown         int               `mapstructure:"alert_cooldown"`
+	SustainedCycles       int               `mapstructure:"sustained_cycles"`
+	ConsecutiveCandles    int               `mapstructure:"consecutive_candles"`
+	CandleInterval        string            `mapstructure:"candle_interval"`
+	MinTradeSize          float64           `mapstructure:"min_trade_size"`
+	VolumeSource          string            `mapstructure:"volume_source"`
+	MaxAlertsPerMinute    int               `mapstructure:"max_alerts_per_minute"`
+	DelistNotifications   bool              `mapstructure:"delist_notifications"`
 }
 
 type DatabaseConfig struct {
-	Path string `mapstructure:"path"`
+	Path               string `mapstructure:"path"`
+	AlertRetentionDays int    `mapstructure:"alert_retention_days"`
+}
+
+type DisplayConfig struct {
+	Currency  string  `mapstructure:"currency"`
+	Rate      float64 `mapstructure:"rate"`
+	MaxEmojis int     `mapstructure:"max_emojis"`
+	Separator string  `mapstructure:"symbol_separator"`
 }
 
 type LoggingConfig struct {
-	Level string `mapstructure:"level"`
-	File  string `mapstructure:"file"`
+	Level        string `mapstructure:"level"`
+	File         string `mapstructure:"file"`
+	ErrorBuffer  int    `mapstructure:"error_buffer"`
+	RepeatWindow int    `mapstructure:"repeat_window"`
 }
 
 func Load() (*Config, error) {
 	viper.SetConfigName("config")
-	viper.SetConfigType("yaml")
 	viper.AddConfigPath(".")
 	viper.AddConfigPath("./config")
 	viper.AddConfigPath("/opt/mexc-monitor")
 	viper.AddConfigPath("/etc/mexc-monitor")
 
+	viper.SetEnvPrefix("MEXC_MONITOR")
+	viper.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
+	viper.AutomaticEnv()
+
+	viper.SetDefault("config.auto_write", false)
+	viper.SetDefault("config.format", "yaml")
+	viper.SetDefault("telegram.bot_token", "")
+	viper.SetDefault("telegram.bot_token_file", "")
+	viper.SetDefault("telegram.admins", []int64{})
+	viper.SetDefault("telegram.mode", "polling")
+	viper.SetDefault("telegram.webhook_url", "")
+	viper.SetDefault("telegram.webhook_listen", ":8443")
+	viper.SetDefault("telegram.webhook_secret", "")
+	viper.SetDefault("telegram.send_concurrency", 5)
+	viper.SetDefault("telegram.commands_per_minute", 20)
+	viper.SetDefault("telegram.startup_summary", false)
+	viper.SetDefault("telegram.include_link", false)
 	viper.SetDefault("mexc.websocket_url", "wss://wbs.mexc.com/ws")
+	viper.SetDefault("mexc.websocket_urls", []string{})
+	viper.SetDefault("mexc.tls.ca_file", "")
+	viper.SetDefault("mexc.tls.cert_file", "")
+	viper.SetDefault("mexc.tls.key_file", "")
+	viper.SetDefault("mexc.user_agent", "mexc-monitor/"+Version)
+	viper.SetDefault("mexc.headers", map[string]string{})
+	viper.SetDefault("mexc.mock", false)
 	viper.SetDefault("monitoring.time_interval", 5)
 	viper.SetDefault("monitoring.price_change", 2.0)
 	viper.SetDefault("monitoring.min_volume", 5000)
+	viper.SetDefault("monitoring.always_symbols", []string{})
+	viper.SetDefault("monitoring.include_only", []string{})
+	viper.SetDefault("monitoring.quote_assets", []string{"USDT"})
+	viper.SetDefault("monitoring.default_blacklist", []string{})
+	viper.SetDefault("monitoring.staleness_threshold", 60)
+	viper.SetDefault("monitoring.completed_intervals", false)
+	viper.SetDefault("monitoring.dedupe_prices", true)
+	viper.SetDefault("monitoring.price_epsilon", 0.0)
+	viper.SetDefault("monitoring.ema_alpha", 0.0)
+	viper.SetDefault("monitoring.round_to_tick", true)
+	viper.SetDefault("monitoring.data_timeout", 300)
+	viper.SetDefault("monitoring.missing_volume", "skip")
+	viper.SetDefault("monitoring.acceleration_threshold", 0.0)
+	viper.SetDefault("monitoring.relative_to", "")
+	viper.SetDefault("monitoring.change_reference", "interval")
+	viper.SetDefault("monitoring.session_open", "00:00")
+	viper.SetDefault("monitoring.gap_threshold", 60)
+	viper.SetDefault("monitoring.default_source", "rest")
+	viper.SetDefault("monitoring.data_sources", map[string]string{})
+	viper.SetDefault("monitoring.analysis_workers", 4)
+	viper.SetDefault("monitoring.notify_slow_analysis", false)
+	viper.SetDefault("monitoring.alert_cooldown", 0)
+	viper.SetDefault("monitoring.sustained_cycles", 3)
+	viper.SetDefault("monitoring.consecutive_candles", 0)
+	viper.SetDefault("monitoring.candle_interval", "5m")
+	viper.SetDefault("monitoring.min_trade_size", 0)
+	viper.SetDefault("monitoring.volume_source", "trades")
+	viper.SetDefault("monitoring.max_alerts_per_minute", 0)
+	viper.SetDefault("monitoring.delist_notifications", true)
 	viper.SetDefault("database.path", "data/monitor.db")
+	viper.SetDefault("database.alert_retention_days", 90)
 	viper.SetDefault("logging.level", "info")
 	viper.SetDefault("logging.file", "logs/monitor.log")
+	viper.SetDefault("logging.error_buffer", 100)
+	viper.SetDefault("logging.repeat_window", 60)
+	viper.SetDefault("display.currency", "USD")
+	viper.SetDefault("display.rate", 1.0)
+	viper.SetDefault("display.max_emojis", 5)
+	viper.SetDefault("display.symbol_separator", "")
 
 	if err := viper.ReadInConfig(); err != nil {
 		if _, ok := err.(viper.ConfigFileNotFoundError); ok {
-			viper.WriteConfigAs("config.yaml")
+			if viper.GetBool("config.auto_write") {
+				if err := writeDefaultConfig(viper.GetString("config.format")); err != nil {
+					log.Warnf("Failed to write default config, continuing with defaults: %v", err)
+				}
+			} else {
+				log.Info("Config file not found, using defaults and MEXC_MONITOR_* environment variables")
+			}
 		} else {
 			return nil, err
 		}
 	}
 
+	if used := viper.ConfigFileUsed(); used != "" {
+		log.Infof("Using config file %s", used)
+	}
+
 	var config Config
 	if err := viper.Unmarshal(&config); err != nil {
 		return nil, err
 	}
 
+	if config.Telegram.BotTokenFile != "" {
+		token, err := readSecretFile(config.Telegram.BotTokenFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read telegram.bot_token_file: %w", err)
+		}
+		config.Telegram.BotToken = token
+	}
+
 	return &config, nil
 }
+
+func writeDefaultConfig(format string) error {
+	format = strings.ToLower(strings.TrimPrefix(format, "."))
+	supported := false
+	for _, ext := range viper.SupportedExts {
+		if ext == format {
+			supported = true
+			break
+		}
+	}
+	if !supported {
+		return fmt.Errorf("unsupported config.format %q, expected one of: %s", format, strings.Join(viper.SupportedExts, ", "))
+	}
+
+	path := "config." + format
+	log.Infof("Config file not found, writing defaults to %s", path)
+	return viper.WriteConfigAs(path)
+}
+
+func readSecretFile(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+
+	secret := strings.TrimSpace(string(data))
+	if secret == "" {
+		return "", fmt.Errorf("%s is empty", path)
+	}
+	return secret, nil
+}
@@ -0,0 +1,1009 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+type sqliteStore struct {
+	db *sql.DB
+}
+
+func New(dbPath string) (Store, error) {
+	db, err := sql.Open("sqlite3", dbPath)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := db.Ping(); err != nil {
+		return nil, err
+	}
+
+	if err := createTables(db); err != nil {
+		return nil, err
+	}
+
+	return &sqliteStore{db: db}, nil
+}
+
+func (d *sqliteStore) Close() error {
+	return d.db.Close()
+}
+
+func (d *sqliteStore) Backup(path string) error {
+	_, err := d.db.Exec(`VACUUM INTO ?`, path)
+	return err
+}
+
+func createTables(db *sql.DB) error {
+	_, err := db.Exec(`
+		CREATE TABLE IF NOT EXISTS settings (
+			key TEXT PRIMARY KEY,
+			value TEXT NOT NULL
+		)
+	`)
+	if err != nil {
+		return err
+	}
+
+	_, err = db.Exec(`
+		CREATE TABLE IF NOT EXISTS blacklist (
+			symbol TEXT PRIMARY KEY,
+			expires_at DATETIME NOT NULL,
+			permanent INTEGER NOT NULL DEFAULT 0
+		)
+	`)
+	if err != nil {
+		return err
+	}
+
+	if err := addColumnIfMissing(db, "blacklist", "permanent", "INTEGER NOT NULL DEFAULT 0"); err != nil {
+		return err
+	}
+
+	_, err = db.Exec(`
+		CREATE TABLE IF NOT EXISTS user_settings (
+			chat_id INTEGER NOT NULL,
+			key TEXT NOT NULL,
+			value TEXT NOT NULL,
+			PRIMARY KEY (chat_id, key)
+		)
+	`)
+	if err != nil {
+		return err
+	}
+
+	_, err = db.Exec(`
+		CREATE TABLE IF NOT EXISTS maintenance_windows (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			starts_at DATETIME NOT NULL,
+			ends_at DATETIME NOT NULL
+		)
+	`)
+	if err != nil {
+		return err
+	}
+
+	_, err = db.Exec(`
+		CREATE TABLE IF NOT EXISTS price_alerts (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			chat_id INTEGER NOT NULL,
+			symbol TEXT NOT NULL,
+			above INTEGER NOT NULL,
+			level REAL NOT NULL,
+			repeat INTEGER NOT NULL DEFAULT 0,
+			armed INTEGER NOT NULL DEFAULT 1,
+			created_at DATETIME NOT NULL
+		)
+	`)
+	if err != nil {
+		return err
+	}
+
+	_, err = db.Exec(`
+		CREATE TABLE IF NOT EXISTS alert_cooldowns (
+			symbol TEXT PRIMARY KEY,
+			last_alert_at DATETIME NOT NULL
+		)
+	`)
+	if err != nil {
+		return err
+	}
+
+	_, err = db.Exec(`
+		CREATE TABLE IF NOT EXISTS symbol_overrides (
+			symbol TEXT NOT NULL,
+			key TEXT NOT NULL,
+			value TEXT NOT NULL,
+			PRIMARY KEY (symbol, key)
+		)
+	`)
+	if err != nil {
+		return err
+	}
+
+	_, err = db.Exec(`
+		CREATE TABLE IF NOT EXISTS alerts (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			symbol TEXT NOT NULL,
+			direction TEXT NOT NULL,
+			change REAL NOT NULL,
+			tier INTEGER NOT NULL,
+			mode TEXT NOT NULL,
+			price REAL NOT NULL,
+			volume INTEGER NOT NULL,
+			interval INTEGER NOT NULL,
+			created_at DATETIME NOT NULL
+		);
+		CREATE INDEX IF NOT EXISTS idx_alerts_created_at ON alerts (created_at);
+		CREATE INDEX IF NOT EXISTS idx_alerts_symbol_created_at ON alerts (symbol, created_at);
+		CREATE INDEX IF NOT EXISTS idx_alerts_direction_created_at ON alerts (direction, created_at)
+	`)
+	if err != nil {
+		return err
+	}
+
+	_, err = db.Exec(`
+		CREATE TABLE IF NOT EXISTS user_alert_counts (
+			chat_id INTEGER PRIMARY KEY,
+			day TEXT NOT NULL,
+			count INTEGER NOT NULL
+		)
+	`)
+	if err != nil {
+		return err
+	}
+
+	_, err = db.Exec(`
+		CREATE TABLE IF NOT EXISTS watchlist (
+			chat_id INTEGER NOT NULL,
+			symbol TEXT NOT NULL,
+			added_at DATETIME NOT NULL,
+			PRIMARY KEY (chat_id, symbol)
+		);
+		CREATE INDEX IF NOT EXISTS idx_watchlist_symbol ON watchlist (symbol)
+	`)
+	if err != nil {
+		return err
+	}
+
+	_, err = db.Exec(`
+		CREATE TABLE IF NOT EXISTS user_mutes (
+			chat_id INTEGER NOT NULL,
+			symbol TEXT NOT NULL,
+			expires_at DATETIME NOT NULL,
+			PRIMARY KEY (chat_id, symbol)
+		)
+	`)
+	if err != nil {
+		return err
+	}
+
+	_, err = db.Exec(`
+		CREATE TABLE IF NOT EXISTS sent_alerts (
+			key TEXT PRIMARY KEY,
+			expires_at DATETIME NOT NULL
+		)
+	`)
+	if err != nil {
+		return err
+	}
+
+	_, err = db.Exec(`
+		CREATE TABLE IF NOT EXISTS users (
+			chat_id INTEGER PRIMARY KEY,
+			subscribed BOOLEAN NOT NULL DEFAULT 1,
+			added_at DATETIME NOT NULL
+		)
+	`)
+	if err != nil {
+		return err
+	}
+
+	_, err = db.Exec(`
+		CREATE TABLE IF NOT EXISTS settings_audit (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			changed_by INTEGER NOT NULL,
+			key TEXT NOT NULL,
+			old_value TEXT NOT NULL,
+			new_value TEXT NOT NULL,
+			changed_at DATETIME NOT NULL
+		)
+	`)
+	if err != nil {
+		return err
+	}
+
+	_, err = db.Exec(`
+		INSERT OR IGNORE INTO settings (key, value) VALUES 
+		('time_interval', '5'),
+		('price_change', '2.0'),
+		('min_volume', '5000'),
+		('min_volume_up', '0'),
+		('min_volume_down', '0'),
+		('retention', '10')
+	`)
+	return err
+}
+
+func addColumnIfMissing(db *sql.DB, table, column, definition string) error {
+	rows, err := db.Query(fmt.Sprintf("PRAGMA table_info(%s)", table))
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			cid       int
+			name      string
+			typ       string
+			notNull   bool
+			dfltValue sql.NullString
+			pk        int
+		)
+		if err := rows.Scan(&cid, &name, &typ, &notNull, &dfltValue, &pk); err != nil {
+			return err
+		}
+		if name == column {
+			return nil
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	_, err = db.Exec(fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s", table, column, definition))
+	return err
+}
+
+func (d *sqliteStore) GetSettings() (*Settings, error) {
+	rows, err := d.db.Query("SELECT key, value FROM settings")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	settings := &Settings{}
+	for rows.Next() {
+		var key, value string
+		if err := rows.Scan(&key, &value); err != nil {
+			return nil, err
+		}
+
+		switch key {
+		case "time_interval":
+			if _, err := fmt.Sscanf(value, "%d", &settings.TimeInterval); err != nil {
+				return nil, err
+			}
+		case "price_change":
+			if _, err := fmt.Sscanf(value, "%f", &settings.PriceChange); err != nil {
+				return nil, err
+			}
+		case "min_volume":
+			if _, err := fmt.Sscanf(value, "%d", &settings.MinVolume); err != nil {
+				return nil, err
+			}
+		case "min_volume_up":
+			if _, err := fmt.Sscanf(value, "%d", &settings.MinVolumeUp); err != nil {
+				return nil, err
+			}
+		case "min_volume_down":
+			if _, err := fmt.Sscanf(value, "%d", &settings.MinVolumeDown); err != nil {
+				return nil, err
+			}
+		case "retention":
+			if _, err := fmt.Sscanf(value, "%d", &settings.Retention); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return settings, nil
+}
+
+func (d *sqliteStore) UpdateSettings(settings *Settings) error {
+	tx, err := d.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec("UPDATE settings SET value = ? WHERE key = ?",
+		fmt.Sprintf("%d", settings.TimeInterval), "time_interval")
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec("UPDATE settings SET value = ? WHERE key = ?",
+		fmt.Sprintf("%.2f", settings.PriceChange), "price_change")
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec("UPDATE settings SET value = ? WHERE key = ?",
+		fmt.Sprintf("%d", settings.MinVolume), "min_volume")
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec("UPDATE settings SET value = ? WHERE key = ?",
+		fmt.Sprintf("%d", settings.MinVolumeUp), "min_volume_up")
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec("UPDATE settings SET value = ? WHERE key = ?",
+		fmt.Sprintf("%d", settings.MinVolumeDown), "min_volume_down")
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec("UPDATE settings SET value = ? WHERE key = ?",
+		fmt.Sprintf("%d", settings.Retention), "retention")
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (d *sqliteStore) AddSettingsAudit(changedBy int64, key, oldValue, newValue string) error {
+	_, err := d.db.Exec(`INSERT INTO settings_audit (changed_by, key, old_value, new_value, changed_at)
+		VALUES (?, ?, ?, ?, ?)`, changedBy, key, oldValue, newValue, time.Now())
+	return err
+}
+
+func (d *sqliteStore) GetUserSettings(chatID int64) (*UserSettings, error) {
+	rows, err := d.db.Query("SELECT key, value FROM user_settings WHERE chat_id = ?", chatID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	settings := &UserSettings{
+		Format:   "detailed",
+		Timezone: "UTC",
+	}
+	for rows.Next() {
+		var key, value string
+		if err := rows.Scan(&key, &value); err != nil {
+			return nil, err
+		}
+
+		switch key {
+		case "format":
+			settings.Format = value
+		case "max_alerts_per_day":
+			if v, err := strconv.Atoi(value); err == nil {
+				settings.MaxAlertsPerDay = v
+			}
+		case "timezone":
+			settings.Timezone = value
+		case "sessions":
+			settings.Sessions = value
+		case "events":
+			settings.Events = value
+		}
+	}
+
+	return settings, rows.Err()
+}
+
+func (d *sqliteStore) UpdateUserSettings(chatID int64, settings *UserSettings) error {
+	tx, err := d.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	values := map[string]string{
+		"format":             settings.Format,
+		"max_alerts_per_day": strconv.Itoa(settings.MaxAlertsPerDay),
+		"timezone":           settings.Timezone,
+		"sessions":           settings.Sessions,
+		"events":             settings.Events,
+	}
+	for key, value := range values {
+		_, err = tx.Exec("INSERT OR REPLACE INTO user_settings (chat_id, key, value) VALUES (?, ?, ?)",
+			chatID, key, value)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (d *sqliteStore) IncrementAlertCount(chatID int64, day string) (int, error) {
+	_, err := d.db.Exec(`INSERT INTO user_alert_counts (chat_id, day, count) VALUES (?, ?, 1)
+		ON CONFLICT(chat_id) DO UPDATE SET
+			count = CASE WHEN day = excluded.day THEN count + 1 ELSE 1 END,
+			day = excluded.day`, chatID, day)
+	if err != nil {
+		return 0, err
+	}
+
+	var count int
+	err = d.db.QueryRow("SELECT count FROM user_alert_counts WHERE chat_id = ?", chatID).Scan(&count)
+	return count, err
+}
+
+const upsertBlacklistQuery = `INSERT INTO blacklist (symbol, expires_at) VALUES (?, ?)
+	ON CONFLICT(symbol) DO UPDATE SET expires_at = MAX(expires_at, excluded.expires_at)`
+
+func (d *sqliteStore) AddToBlacklist(symbol string, duration time.Duration) error {
+	expiresAt := time.Now().Add(duration)
+	_, err := d.db.Exec(upsertBlacklistQuery, symbol, expiresAt)
+	return err
+}
+
+func (d *sqliteStore) AddManyToBlacklist(symbols []string, duration time.Duration) error {
+	tx, err := d.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	expiresAt := time.Now().Add(duration)
+	for _, symbol := range symbols {
+		if _, err := tx.Exec(upsertBlacklistQuery, symbol, expiresAt); err != nil {
+			return fmt.Errorf("%s: %w", symbol, err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (d *sqliteStore) ApplyDefaultBlacklist(defaults []BlacklistDefault) (int, error) {
+	tx, err := d.db.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	permanent := make(map[string]bool)
+	for _, entry := range defaults {
+		if entry.Duration <= 0 {
+			permanent[entry.Symbol] = true
+		}
+	}
+
+	rows, err := tx.Query("SELECT symbol FROM blacklist WHERE permanent = 1")
+	if err != nil {
+		return 0, err
+	}
+	var stale []string
+	for rows.Next() {
+		var symbol string
+		if err := rows.Scan(&symbol); err != nil {
+			rows.Close()
+			return 0, err
+		}
+		if !permanent[symbol] {
+			stale = append(stale, symbol)
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+
+	for _, symbol := range stale {
+		if _, err := tx.Exec("DELETE FROM blacklist WHERE symbol = ?", symbol); err != nil {
+			return 0, fmt.Errorf("%s: %w", symbol, err)
+		}
+	}
+
+	applied := 0
+	now := time.Now()
+	for _, entry := range defaults {
+		var result sql.Result
+		if entry.Duration <= 0 {
+			result, err = tx.Exec(`INSERT INTO blacklist (symbol, expires_at, permanent) VALUES (?, ?, 1)
+				ON CONFLICT(symbol) DO UPDATE SET permanent = 1 WHERE permanent = 0`, entry.Symbol, now)
+		} else {
+			result, err = tx.Exec("INSERT OR IGNORE INTO blacklist (symbol, expires_at) VALUES (?, ?)",
+				entry.Symbol, now.Add(entry.Duration))
+		}
+		if err != nil {
+			return 0, fmt.Errorf("%s: %w", entry.Symbol, err)
+		}
+		affected, err := result.RowsAffected()
+		if err != nil {
+			return 0, err
+		}
+		applied += int(affected)
+	}
+
+	return applied, tx.Commit()
+}
+
+func (d *sqliteStore) RemoveFromBlacklist(symbol string) error {
+	_, err := d.db.Exec("DELETE FROM blacklist WHERE symbol = ?", symbol)
+	return err
+}
+
+func (d *sqliteStore) GetBlacklist() ([]BlacklistEntry, error) {
+	rows, err := d.db.Query(`SELECT symbol, expires_at, permanent FROM blacklist
+		WHERE permanent = 1 OR expires_at > ? ORDER BY permanent, expires_at, symbol`, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var entries []BlacklistEntry
+	for rows.Next() {
+		var entry BlacklistEntry
+		if err := rows.Scan(&entry.Symbol, &entry.ExpiresAt, &entry.Permanent); err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+
+	return entries, nil
+}
+
+func (d *sqliteStore) IsBlacklisted(symbol string) (bool, error) {
+	var count int
+	err := d.db.QueryRow("SELECT COUNT(*) FROM blacklist WHERE symbol = ? AND (permanent = 1 OR expires_at > ?)",
+		symbol, time.Now()).Scan(&count)
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+func (d *sqliteStore) GetBlacklistExpiry(symbol string) (time.Time, bool, error) {
+	var expiresAt time.Time
+	var permanent bool
+	err := d.db.QueryRow("SELECT expires_at, permanent FROM blacklist WHERE symbol = ? AND (permanent = 1 OR expires_at > ?)",
+		symbol, time.Now()).Scan(&expiresAt, &permanent)
+	if err == sql.ErrNoRows {
+		return time.Time{}, false, nil
+	}
+	if err != nil {
+		return time.Time{}, false, err
+	}
+	if permanent {
+		return time.Time{}, true, nil
+	}
+	return expiresAt, true, nil
+}
+
+func (d *sqliteStore) CleanupExpiredBlacklist() error {
+	_, err := d.db.Exec("DELETE FROM blacklist WHERE permanent = 0 AND expires_at <= ?", time.Now())
+	return err
+}
+
+func (d *sqliteStore) MuteSymbol(chatID int64, symbol string, duration time.Duration) error {
+	_, err := d.db.Exec(`INSERT INTO user_mutes (chat_id, symbol, expires_at) VALUES (?, ?, ?)
+		ON CONFLICT(chat_id, symbol) DO UPDATE SET expires_at = MAX(expires_at, excluded.expires_at)`,
+		chatID, symbol, time.Now().Add(duration))
+	return err
+}
+
+func (d *sqliteStore) IsMuted(chatID int64, symbol string) (bool, error) {
+	var count int
+	err := d.db.QueryRow("SELECT COUNT(*) FROM user_mutes WHERE chat_id = ? AND symbol = ? AND expires_at > ?",
+		chatID, symbol, time.Now()).Scan(&count)
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+func (d *sqliteStore) CleanupExpiredMutes() error {
+	_, err := d.db.Exec("DELETE FROM user_mutes WHERE expires_at <= ?", time.Now())
+	return err
+}
+
+func (d *sqliteStore) MarkAlertSent(key string, expiresAt time.Time) (bool, error) {
+	result, err := d.db.Exec(`INSERT INTO sent_alerts (key, expires_at) VALUES (?, ?)
+		ON CONFLICT(key) DO UPDATE SET expires_at = excluded.expires_at WHERE sent_alerts.expires_at <= ?`,
+		key, expiresAt, time.Now())
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected > 0, nil
+}
+
+func (d *sqliteStore) CleanupExpiredAlertKeys() error {
+	_, err := d.db.Exec("DELETE FROM sent_alerts WHERE expires_at <= ?", time.Now())
+	return err
+}
+
+func (d *sqliteStore) AddMaintenanceWindow(startsAt, endsAt time.Time) (int64, error) {
+	result, err := d.db.Exec("INSERT INTO maintenance_windows (starts_at, ends_at) VALUES (?, ?)",
+		startsAt.UTC(), endsAt.UTC())
+	if err != nil {
+		return 0, err
+	}
+	return result.LastInsertId()
+}
+
+func (d *sqliteStore) RemoveMaintenanceWindow(id int64) (bool, error) {
+	result, err := d.db.Exec("DELETE FROM maintenance_windows WHERE id = ?", id)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected > 0, nil
+}
+
+func (d *sqliteStore) GetMaintenanceWindows() ([]MaintenanceWindow, error) {
+	rows, err := d.db.Query("SELECT id, starts_at, ends_at FROM maintenance_windows WHERE ends_at > ? ORDER BY starts_at",
+		time.Now().UTC())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var windows []MaintenanceWindow
+	for rows.Next() {
+		var window MaintenanceWindow
+		if err := rows.Scan(&window.ID, &window.StartsAt, &window.EndsAt); err != nil {
+			return nil, err
+		}
+		windows = append(windows, window)
+	}
+
+	return windows, rows.Err()
+}
+
+func (d *sqliteStore) IsInMaintenance(t time.Time) (bool, error) {
+	var count int
+	err := d.db.QueryRow("SELECT COUNT(*) FROM maintenance_windows WHERE starts_at <= ? AND ends_at > ?",
+		t.UTC(), t.UTC()).Scan(&count)
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+func (d *sqliteStore) CleanupExpiredMaintenance() error {
+	_, err := d.db.Exec("DELETE FROM maintenance_windows WHERE ends_at <= ?", time.Now().UTC())
+	return err
+}
+
+func (d *sqliteStore) AddPriceAlert(alert *PriceAlert) (int64, error) {
+	result, err := d.db.Exec(`INSERT INTO price_alerts (chat_id, symbol, above, level, repeat, armed, created_at)
+		VALUES (?, ?, ?, ?, ?, 1, ?)`,
+		alert.ChatID, alert.Symbol, alert.Above, alert.Level, alert.Repeat, time.Now())
+	if err != nil {
+		return 0, err
+	}
+	return result.LastInsertId()
+}
+
+func (d *sqliteStore) GetPriceAlerts(chatID int64) ([]PriceAlert, error) {
+	return d.queryPriceAlerts("WHERE chat_id = ? ORDER BY symbol, level", chatID)
+}
+
+func (d *sqliteStore) GetAllPriceAlerts() ([]PriceAlert, error) {
+	return d.queryPriceAlerts("ORDER BY id")
+}
+
+func (d *sqliteStore) queryPriceAlerts(clause string, args ...interface{}) ([]PriceAlert, error) {
+	rows, err := d.db.Query("SELECT id, chat_id, symbol, above, level, repeat, armed, created_at FROM price_alerts "+clause,
+		args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var alerts []PriceAlert
+	for rows.Next() {
+		var alert PriceAlert
+		if err := rows.Scan(&alert.ID, &alert.ChatID, &alert.Symbol, &alert.Above, &alert.Level,
+			&alert.Repeat, &alert.Armed, &alert.CreatedAt); err != nil {
+			return nil, err
+		}
+		alerts = append(alerts, alert)
+	}
+
+	return alerts, nil
+}
+
+func (d *sqliteStore) SetPriceAlertArmed(id int64, armed bool) error {
+	_, err := d.db.Exec("UPDATE price_alerts SET armed = ? WHERE id = ?", armed, id)
+	return err
+}
+
+func (d *sqliteStore) RemovePriceAlert(chatID, id int64) (bool, error) {
+	result, err := d.db.Exec("DELETE FROM price_alerts WHERE id = ? AND chat_id = ?", id, chatID)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected > 0, nil
+}
+
+func (d *sqliteStore) DeletePriceAlert(id int64) error {
+	_, err := d.db.Exec("DELETE FROM price_alerts WHERE id = ?", id)
+	return err
+}
+
+func (d *sqliteStore) GetLastAlert(symbol string) (time.Time, bool, error) {
+	var lastAlertAt time.Time
+	err := d.db.QueryRow("SELECT last_alert_at FROM alert_cooldowns WHERE symbol = ?", symbol).Scan(&lastAlertAt)
+	if err == sql.ErrNoRows {
+		return time.Time{}, false, nil
+	}
+	if err != nil {
+		return time.Time{}, false, err
+	}
+	return lastAlertAt, true, nil
+}
+
+func (d *sqliteStore) GetLastAlerts() (map[string]time.Time, error) {
+	rows, err := d.db.Query("SELECT symbol, last_alert_at FROM alert_cooldowns")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	lastAlerts := make(map[string]time.Time)
+	for rows.Next() {
+		var symbol string
+		var lastAlertAt time.Time
+		if err := rows.Scan(&symbol, &lastAlertAt); err != nil {
+			return nil, err
+		}
+		lastAlerts[symbol] = lastAlertAt
+	}
+
+	return lastAlerts, nil
+}
+
+func (d *sqliteStore) SetLastAlert(symbol string, at time.Time) error {
+	_, err := d.db.Exec("INSERT OR REPLACE INTO alert_cooldowns (symbol, last_alert_at) VALUES (?, ?)",
+		symbol, at)
+	return err
+}
+
+func (d *sqliteStore) SetSymbolCooldown(symbol string, seconds int) error {
+	if seconds <= 0 {
+		_, err := d.db.Exec("DELETE FROM symbol_overrides WHERE symbol = ? AND key = 'cooldown'", symbol)
+		return err
+	}
+
+	_, err := d.db.Exec("INSERT OR REPLACE INTO symbol_overrides (symbol, key, value) VALUES (?, 'cooldown', ?)",
+		symbol, strconv.Itoa(seconds))
+	return err
+}
+
+func (d *sqliteStore) GetSymbolCooldowns() (map[string]int, error) {
+	rows, err := d.db.Query("SELECT symbol, value FROM symbol_overrides WHERE key = 'cooldown'")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	cooldowns := make(map[string]int)
+	for rows.Next() {
+		var symbol, value string
+		if err := rows.Scan(&symbol, &value); err != nil {
+			return nil, err
+		}
+		seconds, err := strconv.Atoi(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid cooldown override for %s: %w", symbol, err)
+		}
+		cooldowns[symbol] = seconds
+	}
+
+	return cooldowns, rows.Err()
+}
+
+func (d *sqliteStore) SaveAlert(alert *AlertRecord) (int64, error) {
+	result, err := d.db.Exec(`INSERT INTO alerts
+		(symbol, direction, change, tier, mode, price, volume, interval, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		alert.Symbol, alert.Direction, alert.Change, alert.Tier, alert.Mode,
+		alert.Price, alert.Volume, alert.Interval, alert.CreatedAt)
+	if err != nil {
+		return 0, err
+	}
+	return result.LastInsertId()
+}
+
+func (d *sqliteStore) PruneAlerts(before time.Time) (int64, error) {
+	result, err := d.db.Exec("DELETE FROM alerts WHERE created_at < ?", before)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+func (d *sqliteStore) QueryAlerts(filter AlertFilter) ([]AlertRecord, error) {
+	var conditions []string
+	var args []interface{}
+
+	if filter.Symbol != "" {
+		conditions = append(conditions, "symbol = ?")
+		args = append(args, filter.Symbol)
+	}
+	if filter.Direction != "" {
+		conditions = append(conditions, "direction = ?")
+		args = append(args, filter.Direction)
+	}
+	if !filter.Since.IsZero() {
+		conditions = append(conditions, "created_at >= ?")
+		args = append(args, filter.Since)
+	}
+	if !filter.Until.IsZero() {
+		conditions = append(conditions, "created_at < ?")
+		args = append(args, filter.Until)
+	}
+
+	query := "SELECT id, symbol, direction, change, tier, mode, price, volume, interval, created_at FROM alerts"
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	query += " ORDER BY created_at DESC"
+	if filter.Limit > 0 {
+		query += " LIMIT ?"
+		args = append(args, filter.Limit)
+	}
+
+	rows, err := d.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var alerts []AlertRecord
+	for rows.Next() {
+		var alert AlertRecord
+		if err := rows.Scan(&alert.ID, &alert.Symbol, &alert.Direction, &alert.Change, &alert.Tier,
+			&alert.Mode, &alert.Price, &alert.Volume, &alert.Interval, &alert.CreatedAt); err != nil {
+			return nil, err
+		}
+		alerts = append(alerts, alert)
+	}
+
+	return alerts, rows.Err()
+}
+
+func (d *sqliteStore) AddToWatchlist(chatID int64, symbol string) (bool, error) {
+	result, err := d.db.Exec("INSERT OR IGNORE INTO watchlist (chat_id, symbol, added_at) VALUES (?, ?, ?)",
+		chatID, symbol, time.Now())
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected > 0, nil
+}
+
+func (d *sqliteStore) RemoveFromWatchlist(chatID int64, symbol string) (bool, error) {
+	result, err := d.db.Exec("DELETE FROM watchlist WHERE chat_id = ? AND symbol = ?", chatID, symbol)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected > 0, nil
+}
+
+func (d *sqliteStore) GetWatchlist(chatID int64) ([]string, error) {
+	rows, err := d.db.Query("SELECT symbol FROM watchlist WHERE chat_id = ? ORDER BY symbol", chatID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var symbols []string
+	for rows.Next() {
+		var symbol string
+		if err := rows.Scan(&symbol); err != nil {
+			return nil, err
+		}
+		symbols = append(symbols, symbol)
+	}
+
+	return symbols, rows.Err()
+}
+
+func (d *sqliteStore) AddUser(chatID int64) error {
+	_, err := d.db.Exec(`INSERT INTO users (chat_id, subscribed, added_at) VALUES (?, 1, ?)
+		ON CONFLICT(chat_id) DO UPDATE SET subscribed = 1`, chatID, time.Now())
+	return err
+}
+
+func (d *sqliteStore) RemoveUser(chatID int64) error {
+	_, err := d.db.Exec("UPDATE users SET subscribed = 0 WHERE chat_id = ?", chatID)
+	return err
+}
+
+func (d *sqliteStore) GetUsers() ([]int64, error) {
+	rows, err := d.db.Query("SELECT chat_id FROM users WHERE subscribed = 1 ORDER BY chat_id")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var chatIDs []int64
+	for rows.Next() {
+		var chatID int64
+		if err := rows.Scan(&chatID); err != nil {
+			return nil, err
+		}
+		chatIDs = append(chatIDs, chatID)
+	}
+
+	return chatIDs, rows.Err()
+}
+
+func (d *sqliteStore) GetWatchers(symbol string) ([]int64, error) {
+	rows, err := d.db.Query("SELECT chat_id FROM watchlist WHERE symbol = ? ORDER BY chat_id", symbol)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var chatIDs []int64
+	for rows.Next() {
+		var chatID int64
+		if err := rows.Scan(&chatID); err != nil {
+			return nil, err
+		}
+		chatIDs = append(chatIDs, chatID)
+	}
+
+	return chatIDs, rows.Err()
+}
+
+func (d *sqliteStore) RemoveSymbolFromWatchlists(symbol string) (int64, error) {
+	result, err := d.db.Exec("DELETE FROM watchlist WHERE symbol = ?", symbol)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+func (d *sqliteStore) AddManyToWatchlist(chatID int64, symbols []string) (int, error) {
+	tx, err := d.db.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	added := 0
+	now := time.Now()
+	for _, symbol := range symbols {
+		result, err := tx.Exec("INSERT OR IGNORE INTO watchlist (chat_id, symbol, added_at) VALUES (?, ?, ?)",
+			chatID, symbol, now)
+		if err != nil {
+			return 0, fmt.Errorf("%s: %w", symbol, err)
+		}
+		affected, err := result.RowsAffected()
+		if err != nil {
+			return 0, err
+		}
+		added += int(affected)
+	}
+
+	return added, tx.Commit()
+}
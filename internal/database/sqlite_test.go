@@ -0,0 +1,86 @@
+package database
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func newTestStore(t *testing.T) Store {
+	t.Helper()
+
+	store, err := New(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("failed to open test database: %v", err)
+	}
+	t.Cleanup(func() { store.Close() })
+	return store
+}
+
+func TestMaintenanceWindowsIgnoreLocalZone(t *testing.T) {
+	store := newTestStore(t)
+
+	zone := time.FixedZone("UTC+5", 5*60*60)
+	now := time.Now().In(zone)
+
+	if _, err := store.AddMaintenanceWindow(now.Add(-time.Hour).UTC(), now.Add(time.Hour).UTC()); err != nil {
+		t.Fatalf("AddMaintenanceWindow() error = %v", err)
+	}
+	if _, err := store.AddMaintenanceWindow(now.Add(3*time.Hour).In(zone), now.Add(4*time.Hour).In(zone)); err != nil {
+		t.Fatalf("AddMaintenanceWindow() error = %v", err)
+	}
+
+	tests := []struct {
+		at   time.Time
+		want bool
+	}{
+		{at: now, want: true},
+		{at: now.UTC(), want: true},
+		{at: now.Add(2 * time.Hour), want: false},
+		{at: now.Add(3*time.Hour + time.Minute), want: true},
+	}
+	for _, tt := range tests {
+		got, err := store.IsInMaintenance(tt.at)
+		if err != nil || got != tt.want {
+			t.Errorf("IsInMaintenance(%s) = %t, %v; want %t", tt.at, got, err, tt.want)
+		}
+	}
+
+	if err := store.CleanupExpiredMaintenance(); err != nil {
+		t.Fatalf("CleanupExpiredMaintenance() error = %v", err)
+	}
+	windows, err := store.GetMaintenanceWindows()
+	if err != nil || len(windows) != 2 {
+		t.Errorf("GetMaintenanceWindows() = %d windows, %v; want 2", len(windows), err)
+	}
+}
+
+func TestBlacklistReAddKeepsLongerExpiry(t *testing.T) {
+	store := newTestStore(t)
+
+	expiry := func() time.Duration {
+		t.Helper()
+		expiresAt, blacklisted, err := store.GetBlacklistExpiry("SCAMUSDT")
+		if err != nil || !blacklisted {
+			t.Fatalf("GetBlacklistExpiry() = %t, %v; want blacklisted", blacklisted, err)
+		}
+		return time.Until(expiresAt)
+	}
+
+	steps := []struct {
+		duration time.Duration
+		want     time.Duration
+	}{
+		{duration: time.Hour, want: time.Hour},
+		{duration: 10 * time.Minute, want: time.Hour},
+		{duration: 2 * time.Hour, want: 2 * time.Hour},
+	}
+	for _, step := range steps {
+		if err := store.AddToBlacklist("SCAMUSDT", step.duration); err != nil {
+			t.Fatalf("AddToBlacklist(%s) error = %v", step.duration, err)
+		}
+		if got := expiry(); got < step.want-time.Minute || got > step.want {
+			t.Errorf("after adding for %s: expires in %s, want about %s", step.duration, got.Round(time.Second), step.want)
+		}
+	}
+}
@@ -0,0 +1,143 @@
+package database
+
+import "time"
+
+type Store interface {
+	Close() error
+	Backup(path string) error
+
+	GetSettings() (*Settings, error)
+	UpdateSettings(settings *Settings) error
+	AddSettingsAudit(changedBy int64, key, oldValue, newValue string) error
+
+	AddUser(chatID int64) error
+	RemoveUser(chatID int64) error
+	GetUsers() ([]int64, error)
+
+	GetUserSettings(chatID int64) (*UserSettings, error)
+	UpdateUserSettings(chatID int64, settings *UserSettings) error
+	IncrementAlertCount(chatID int64, day string) (int, error)
+
+	AddToBlacklist(symbol string, duration time.Duration) error
+	AddManyToBlacklist(symbols []string, duration time.Duration) error
+	ApplyDefaultBlacklist(defaults []BlacklistDefault) (int, error)
+	RemoveFromBlacklist(symbol string) error
+	GetBlacklist() ([]BlacklistEntry, error)
+	IsBlacklisted(symbol string) (bool, error)
+	GetBlacklistExpiry(symbol string) (time.Time, bool, error)
+	CleanupExpiredBlacklist() error
+
+	MuteSymbol(chatID int64, symbol string, duration time.Duration) error
+	IsMuted(chatID int64, symbol string) (bool, error)
+	CleanupExpiredMutes() error
+
+	MarkAlertSent(key string, expiresAt time.Time) (bool, error)
+	CleanupExpiredAlertKeys() error
+
+	AddMaintenanceWindow(startsAt, endsAt time.Time) (int64, error)
+	RemoveMaintenanceWindow(id int64) (bool, error)
+	GetMaintenanceWindows() ([]MaintenanceWindow, error)
+	IsInMaintenance(t time.Time) (bool, error)
+	CleanupExpiredMaintenance() error
+
+	AddPriceAlert(alert *PriceAlert) (int64, error)
+	GetPriceAlerts(chatID int64) ([]PriceAlert, error)
+	GetAllPriceAlerts() ([]PriceAlert, error)
+	SetPriceAlertArmed(id int64, armed bool) error
+	RemovePriceAlert(chatID, id int64) (bool, error)
+	DeletePriceAlert(id int64) error
+
+	GetLastAlert(symbol string) (time.Time, bool, error)
+	GetLastAlerts() (map[string]time.Time, error)
+	SetLastAlert(symbol string, at time.Time) error
+
+	SetSymbolCooldown(symbol string, seconds int) error
+	GetSymbolCooldowns() (map[string]int, error)
+
+	SaveAlert(alert *AlertRecord) (int64, error)
+	PruneAlerts(before time.Time) (int64, error)
+	QueryAlerts(filter AlertFilter) ([]AlertRecord, error)
+
+	AddToWatchlist(chatID int64, symbol string) (bool, error)
+	AddManyToWatchlist(chatID int64, symbols []string) (int, error)
+	RemoveFromWatchlist(chatID int64, symbol string) (bool, error)
+	GetWatchlist(chatID int64) ([]string, error)
+	GetWatchers(symbol string) ([]int64, error)
+	RemoveSymbolFromWatchlists(symbol string) (int64, error)
+}
+
+type Settings struct {
+	TimeInterval  int     `json:"time_interval"`
+	PriceChange   float64 `json:"price_change"`
+	MinVolume     int     `json:"min_volume"`
+	MinVolumeUp   int     `json:"min_volume_up,omitempty"`
+	MinVolumeDown int     `json:"min_volume_down,omitempty"`
+	Retention     int     `json:"retention"`
+}
+
+func (s *Settings) VolumeThreshold(change float64) int {
+	if change > 0 && s.MinVolumeUp > 0 {
+		return s.MinVolumeUp
+	}
+	if change < 0 && s.MinVolumeDown > 0 {
+		return s.MinVolumeDown
+	}
+	return s.MinVolume
+}
+
+type MaintenanceWindow struct {
+	ID       int64     `json:"id"`
+	StartsAt time.Time `json:"starts_at"`
+	EndsAt   time.Time `json:"ends_at"`
+}
+
+type PriceAlert struct {
+	ID        int64     `json:"id"`
+	ChatID    int64     `json:"chat_id"`
+	Symbol    string    `json:"symbol"`
+	Above     bool      `json:"above"`
+	Level     float64   `json:"level"`
+	Repeat    bool      `json:"repeat"`
+	Armed     bool      `json:"armed"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type AlertRecord struct {
+	ID        int64     `json:"id"`
+	Symbol    string    `json:"symbol"`
+	Direction string    `json:"direction"`
+	Change    float64   `json:"change"`
+	Tier      int       `json:"tier"`
+	Mode      string    `json:"mode"`
+	Price     float64   `json:"price"`
+	Volume    int       `json:"volume"`
+	Interval  int       `json:"interval"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type AlertFilter struct {
+	Symbol    string
+	Direction string
+	Since     time.Time
+	Until     time.Time
+	Limit     int
+}
+
+type UserSettings struct {
+	Format          string `json:"format"`
+	MaxAlertsPerDay int    `json:"max_alerts_per_day"`
+	Timezone        string `json:"timezone"`
+	Sessions        string `json:"sessions"`
+	Events          string `json:"events"`
+}
+
+type BlacklistEntry struct {
+	Symbol    string    `json:"symbol"`
+	ExpiresAt time.Time `json:"expires_at"`
+	Permanent bool      `json:"permanent"`
+}
+
+type BlacklistDefault struct {
+	Symbol   string
+	Duration time.Duration
+}
@@ -0,0 +1,116 @@
+package logging
+
+import (
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+type repeatState struct {
+	level log.Level
+	since time.Time
+	count int
+}
+
+type DedupHook struct {
+	mu        sync.Mutex
+	out       io.Writer
+	formatter log.Formatter
+	window    time.Duration
+	seen      map[string]*repeatState
+	now       func() time.Time
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+func NewDedupHook(out io.Writer, formatter log.Formatter, window time.Duration) *DedupHook {
+	h := &DedupHook{
+		out:       out,
+		formatter: formatter,
+		window:    window,
+		seen:      make(map[string]*repeatState),
+		now:       time.Now,
+		done:      make(chan struct{}),
+	}
+	go h.run()
+	return h
+}
+
+func (h *DedupHook) Levels() []log.Level {
+	return log.AllLevels
+}
+
+func (h *DedupHook) Fire(entry *log.Entry) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.flush(false)
+
+	if entry.Level > log.WarnLevel {
+		return h.write(entry)
+	}
+
+	if state, ok := h.seen[entry.Message]; ok {
+		state.count++
+		return nil
+	}
+
+	h.seen[entry.Message] = &repeatState{level: entry.Level, since: h.now()}
+	return h.write(entry)
+}
+
+func (h *DedupHook) Close() {
+	h.closeOnce.Do(func() { close(h.done) })
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.flush(true)
+}
+
+func (h *DedupHook) run() {
+	ticker := time.NewTicker(h.window)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			h.mu.Lock()
+			h.flush(false)
+			h.mu.Unlock()
+		case <-h.done:
+			return
+		}
+	}
+}
+
+func (h *DedupHook) flush(all bool) {
+	now := h.now()
+	for message, state := range h.seen {
+		if !all && now.Sub(state.since) < h.window {
+			continue
+		}
+		delete(h.seen, message)
+		if state.count == 0 {
+			continue
+		}
+
+		summary := log.NewEntry(log.StandardLogger())
+		summary.Time = now
+		summary.Level = state.level
+		summary.Message = fmt.Sprintf("Last message repeated %d times in %s: %s",
+			state.count, now.Sub(state.since).Round(time.Second), message)
+		h.write(summary)
+	}
+}
+
+func (h *DedupHook) write(entry *log.Entry) error {
+	serialized, err := h.formatter.Format(entry)
+	if err != nil {
+		return err
+	}
+	_, err = h.out.Write(serialized)
+	return err
+}
@@ -0,0 +1,60 @@
+package logging
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+func TestDedupHookCollapsesRepeats(t *testing.T) {
+	var out bytes.Buffer
+	hook := NewDedupHook(&out, &log.TextFormatter{DisableTimestamp: true}, time.Minute)
+
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	hook.now = func() time.Time { return now }
+
+	fire := func(level log.Level, message string) {
+		hook.Fire(&log.Entry{Logger: log.New(), Level: level, Message: message})
+	}
+
+	for i := 0; i < 5; i++ {
+		fire(log.ErrorLevel, "connection refused")
+		fire(log.InfoLevel, "analysis complete")
+		now = now.Add(10 * time.Second)
+	}
+	if got := strings.Count(out.String(), "connection refused"); got != 1 {
+		t.Errorf("error logged %d times within window, want 1", got)
+	}
+	if got := strings.Count(out.String(), "analysis complete"); got != 5 {
+		t.Errorf("info logged %d times, want 5", got)
+	}
+
+	now = now.Add(time.Minute)
+	fire(log.ErrorLevel, "connection refused")
+	if !strings.Contains(out.String(), "Last message repeated 4 times") {
+		t.Errorf("summary not written, log:\n%s", out.String())
+	}
+	if got := strings.Count(out.String(), "connection refused"); got != 3 {
+		t.Errorf("error logged %d times after window, want summary and new entry", got)
+	}
+}
+
+func TestDedupHookFlushesOnClose(t *testing.T) {
+	var out bytes.Buffer
+	hook := NewDedupHook(&out, &log.TextFormatter{DisableTimestamp: true}, time.Minute)
+
+	for i := 0; i < 3; i++ {
+		hook.Fire(&log.Entry{Logger: log.New(), Level: log.ErrorLevel, Message: "connection refused"})
+	}
+	if strings.Contains(out.String(), "repeated") {
+		t.Fatalf("summary written before the window ended:\n%s", out.String())
+	}
+
+	hook.Close()
+	if !strings.Contains(out.String(), "Last message repeated 2 times") {
+		t.Errorf("summary not written on close, log:\n%s", out.String())
+	}
+}
@@ -0,0 +1,54 @@
+package logging
+
+import (
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+type Entry struct {
+	Time    time.Time
+	Level   log.Level
+	Message string
+}
+
+type RingHook struct {
+	mu      sync.Mutex
+	entries []Entry
+	next    int
+	full    bool
+}
+
+func NewRingHook(size int) *RingHook {
+	if size < 1 {
+		size = 1
+	}
+	return &RingHook{entries: make([]Entry, size)}
+}
+
+func (h *RingHook) Levels() []log.Level {
+	return []log.Level{log.PanicLevel, log.FatalLevel, log.ErrorLevel, log.WarnLevel}
+}
+
+func (h *RingHook) Fire(entry *log.Entry) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.entries[h.next] = Entry{Time: entry.Time, Level: entry.Level, Message: entry.Message}
+	h.next = (h.next + 1) % len(h.entries)
+	if h.next == 0 {
+		h.full = true
+	}
+	return nil
+}
+
+func (h *RingHook) Entries() []Entry {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if !h.full {
+		return append([]Entry(nil), h.entries[:h.next]...)
+	}
+	return append(append([]Entry(nil), h.entries[h.next:]...), h.entries[:h.next]...)
+}
@@ -0,0 +1,37 @@
+package logging
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	log "github.com/sirupsen/logrus"
+)
+
+func TestRingHookKeepsLatestEntries(t *testing.T) {
+	tests := []struct {
+		size   int
+		fired  int
+		expect string
+	}{
+		{size: 3, fired: 0, expect: ""},
+		{size: 3, fired: 2, expect: "0,1"},
+		{size: 3, fired: 3, expect: "0,1,2"},
+		{size: 3, fired: 7, expect: "4,5,6"},
+	}
+
+	for _, tt := range tests {
+		hook := NewRingHook(tt.size)
+		for i := 0; i < tt.fired; i++ {
+			hook.Fire(&log.Entry{Level: log.WarnLevel, Message: fmt.Sprint(i)})
+		}
+
+		var messages []string
+		for _, entry := range hook.Entries() {
+			messages = append(messages, entry.Message)
+		}
+		if got := strings.Join(messages, ","); got != tt.expect {
+			t.Errorf("size %d, fired %d: entries = %q, want %q", tt.size, tt.fired, got, tt.expect)
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package mexc
+
+import (
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	defaultReconnectDelay    = time.Second
+	defaultMaxReconnectDelay = time.Minute
+	reconnectJitter          = 0.2
+)
+
+func (c *Client) nextReconnectDelay() time.Duration {
+	if c.reconnectDelay == 0 {
+		c.reconnectDelay = c.ReconnectDelay
+	} else {
+		c.reconnectDelay *= 2
+	}
+	if c.reconnectDelay > c.MaxReconnectDelay {
+		c.reconnectDelay = c.MaxReconnectDelay
+	}
+
+	jitter := 1 + reconnectJitter*(2*c.random()-1)
+	return time.Duration(float64(c.reconnectDelay) * jitter)
+}
+
+func (c *Client) resetReconnectDelay() {
+	c.reconnectDelay = 0
+}
+
+func (c *Client) sleepCtx(d time.Duration) {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+	case <-c.ctx.Done():
+	}
+}
+
+func (c *Client) reconnectLoop() {
+	for c.ctx.Err() == nil {
+		delay := c.nextReconnectDelay()
+		log.Infof("Attempting to reconnect in %s...", delay.Round(time.Millisecond))
+		c.sleep(delay)
+		if c.ctx.Err() != nil {
+			return
+		}
+
+		if err := c.reconnect(); err != nil {
+			log.Errorf("Failed to reconnect: %v", err)
+			continue
+		}
+		c.resubscribe()
+		c.emitConnection("reconnect")
+		return
+	}
+}
@@ -0,0 +1,60 @@
+package mexc
+
+import (
+	"testing"
+	"time"
+)
+
+func TestReconnectBackoff(t *testing.T) {
+	c := NewClient(nil, nil)
+	c.random = func() float64 { return 0.5 }
+
+	var delays []time.Duration
+	c.sleep = func(d time.Duration) {
+		delays = append(delays, d)
+		if len(delays) == 8 {
+			c.cancel()
+		}
+	}
+
+	c.reconnectLoop()
+
+	want := []time.Duration{
+		time.Second, 2 * time.Second, 4 * time.Second, 8 * time.Second,
+		16 * time.Second, 32 * time.Second, time.Minute, time.Minute,
+	}
+	if len(delays) != len(want) {
+		t.Fatalf("got %d reconnect delays %v, want %d", len(delays), delays, len(want))
+	}
+	for i := range want {
+		if delays[i] != want[i] {
+			t.Errorf("delay %d = %s, want %s", i, delays[i], want[i])
+		}
+	}
+
+	c.resetReconnectDelay()
+	if got := c.nextReconnectDelay(); got != time.Second {
+		t.Errorf("delay after reset = %s, want 1s", got)
+	}
+}
+
+func TestReconnectBackoffJitter(t *testing.T) {
+	tests := []struct {
+		random float64
+		want   time.Duration
+	}{
+		{random: 0, want: 8 * time.Second},
+		{random: 0.5, want: 10 * time.Second},
+		{random: 1, want: 12 * time.Second},
+	}
+
+	for _, tt := range tests {
+		c := NewClient(nil, nil)
+		c.ReconnectDelay = 10 * time.Second
+		c.random = func() float64 { return tt.random }
+
+		if got := c.nextReconnectDelay(); got != tt.want {
+			t.Errorf("random %.1f: delay = %s, want %s", tt.random, got, tt.want)
+		}
+	}
+}
@@ -2,8 +2,11 @@ package mexc
 
 import (
 	"context"
+	"crypto/tls"
 	"encoding/json"
 	"fmt"
+	"math/rand"
+	"strings"
 	"sync"
 	"time"
 
@@ -11,17 +14,38 @@ import (
 	log "github.com/sirupsen/logrus"
 )
 
+const endpointFailuresBeforeSwitch = 2
+
 type Client struct {
-	conn     *websocket.Conn
-	url      string
-	mu       sync.RWMutex
-	handlers map[string][]EventHandler
-	ctx      context.Context
-	cancel   context.CancelFunc
+	ReconnectDelay    time.Duration
+	MaxReconnectDelay time.Duration
+
+	conn         *websocket.Conn
+	urls         []string
+	urlIndex     int
+	failures     int
+	dialer       *websocket.Dialer
+	mu           sync.RWMutex
+	handlers     map[string][]EventHandler
+	connHandlers map[string][]ConnectionHandler
+	ctx          context.Context
+	cancel       context.CancelFunc
+	mock         bool
+	writeMu      sync.Mutex
+	subsMu       sync.Mutex
+	nextID       int
+	pending      map[int]pendingSubscription
+	streams      map[string]bool
+
+	reconnectDelay time.Duration
+	sleep          func(time.Duration)
+	random         func() float64
 }
 
 type EventHandler func(data interface{})
 
+type ConnectionHandler func()
+
 type TradeData struct {
 	Symbol    string `json:"s"`
 	Price     string `json:"p"`
@@ -31,9 +55,10 @@ type TradeData struct {
 }
 
 type TickerData struct {
-	Symbol    string `json:"s"`
-	Price     string `json:"c"`
-	Timestamp int64  `json:"E"`
+	Symbol      string `json:"s"`
+	Price       string `json:"c"`
+	QuoteVolume string `json:"q"`
+	Timestamp   int64  `json:"E"`
 }
 
 type WebSocketMessage struct {
@@ -43,61 +68,163 @@ type WebSocketMessage struct {
 	Result json.RawMessage `json:"result,omitempty"`
 	Stream string          `json:"stream,omitempty"`
 	Data   json.RawMessage `json:"data,omitempty"`
+	Code   int             `json:"code,omitempty"`
+	Msg    string          `json:"msg,omitempty"`
+}
+
+const dealSideBuy = 1
+
+type pushMessage struct {
+	Channel string          `json:"c"`
+	Symbol  string          `json:"s"`
+	Time    int64           `json:"t"`
+	Data    json.RawMessage `json:"d"`
 }
 
-func NewClient(url string) *Client {
+type dealsPush struct {
+	Deals []struct {
+		Side     int    `json:"S"`
+		Price    string `json:"p"`
+		Quantity string `json:"v"`
+		Time     int64  `json:"t"`
+	} `json:"deals"`
+}
+
+type tickerPush struct {
+	Symbol      string `json:"s"`
+	Price       string `json:"p"`
+	QuoteVolume string `json:"q"`
+}
+
+func NewClient(urls []string, tlsConfig *tls.Config) *Client {
 	ctx, cancel := context.WithCancel(context.Background())
-	return &Client{
-		url:      url,
-		handlers: make(map[string][]EventHandler),
-		ctx:      ctx,
-		cancel:   cancel,
+
+	dialer := *websocket.DefaultDialer
+	dialer.TLSClientConfig = tlsConfig
+
+	seen := make(map[string]bool, len(urls))
+	var endpoints []string
+	for _, url := range urls {
+		if url == "" || seen[url] {
+			continue
+		}
+		seen[url] = true
+		endpoints = append(endpoints, url)
+	}
+
+	c := &Client{
+		ReconnectDelay:    defaultReconnectDelay,
+		MaxReconnectDelay: defaultMaxReconnectDelay,
+		urls:              endpoints,
+		dialer:            &dialer,
+		handlers:          make(map[string][]EventHandler),
+		connHandlers:      make(map[string][]ConnectionHandler),
+		pending:           make(map[int]pendingSubscription),
+		streams:           make(map[string]bool),
+		ctx:               ctx,
+		cancel:            cancel,
+		random:            rand.Float64,
 	}
+	c.sleep = c.sleepCtx
+	return c
 }
 
 func (c *Client) Connect() error {
+	connected, err := c.connect()
+	if err != nil {
+		return err
+	}
+
+	if connected {
+		c.emitConnection("connect")
+	}
+	return nil
+}
+
+func (c *Client) connect() (bool, error) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
 	if c.conn != nil {
-		return nil
+		return false, nil
+	}
+
+	if c.mock {
+		go c.runMock()
+		return true, nil
 	}
 
-	log.Infof("Connecting to MEXC WebSocket: %s", c.url)
+	if len(c.urls) == 0 {
+		return false, fmt.Errorf("no WebSocket endpoints configured")
+	}
+
+	url := c.urls[c.urlIndex]
+	log.Infof("Connecting to MEXC WebSocket: %s", url)
 
-	conn, _, err := websocket.DefaultDialer.Dial(c.url, nil)
+	conn, _, err := c.dialer.Dial(url, nil)
 	if err != nil {
-		return fmt.Errorf("failed to connect to WebSocket: %w", err)
+		c.recordFailure()
+		return false, fmt.Errorf("failed to connect to WebSocket %s: %w", url, err)
 	}
 
 	c.conn = conn
-	log.Info("Successfully connected to MEXC WebSocket")
+	c.failures = 0
+	log.Infof("Successfully connected to MEXC WebSocket: %s", url)
 
 	go c.readMessages()
 
-	return nil
+	return true, nil
+}
+
+func (c *Client) recordFailure() {
+	c.failures++
+	if c.failures < endpointFailuresBeforeSwitch || len(c.urls) < 2 {
+		return
+	}
+
+	c.failures = 0
+	c.urlIndex = (c.urlIndex + 1) % len(c.urls)
+	log.Warnf("Switching to WebSocket endpoint %s", c.urls[c.urlIndex])
+}
+
+func (c *Client) ActiveURL() string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if len(c.urls) == 0 {
+		return ""
+	}
+	return c.urls[c.urlIndex]
 }
 
 func (c *Client) Disconnect() error {
 	c.cancel()
 
 	c.mu.Lock()
-	defer c.mu.Unlock()
-
+	var err error
 	if c.conn != nil {
-		return c.conn.Close()
+		err = c.conn.Close()
 	}
-	return nil
+	c.mu.Unlock()
+
+	c.emitConnection("disconnect")
+	return err
 }
 
 func (c *Client) SubscribeToTrades(symbols []string) error {
-	log.Infof("Trades stream will be available automatically")
-	return nil
+	return c.subscribeStreams(tradesStream, symbols)
 }
 
 func (c *Client) SubscribeToTickers(symbols []string) error {
-	log.Infof("Tickers stream will be available automatically")
-	return nil
+	return c.subscribeStreams(tickersStream, symbols)
+}
+
+func (c *Client) UnsubscribeFromTrades(symbols []string) error {
+	return c.unsubscribeStreams(tradesStream, symbols)
+}
+
+func (c *Client) UnsubscribeFromTickers(symbols []string) error {
+	return c.unsubscribeStreams(tickersStream, symbols)
 }
 
 func (c *Client) OnTrade(handler EventHandler) {
@@ -112,6 +239,34 @@ func (c *Client) OnTicker(handler EventHandler) {
 	c.handlers["ticker"] = append(c.handlers["ticker"], handler)
 }
 
+func (c *Client) OnConnect(handler ConnectionHandler) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.connHandlers["connect"] = append(c.connHandlers["connect"], handler)
+}
+
+func (c *Client) OnDisconnect(handler ConnectionHandler) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.connHandlers["disconnect"] = append(c.connHandlers["disconnect"], handler)
+}
+
+func (c *Client) OnReconnect(handler ConnectionHandler) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.connHandlers["reconnect"] = append(c.connHandlers["reconnect"], handler)
+}
+
+func (c *Client) emitConnection(event string) {
+	c.mu.RLock()
+	handlers := c.connHandlers[event]
+	c.mu.RUnlock()
+
+	for _, handler := range handlers {
+		handler()
+	}
+}
+
 func (c *Client) sendMessage(msg WebSocketMessage) error {
 	c.mu.RLock()
 	defer c.mu.RUnlock()
@@ -125,6 +280,9 @@ func (c *Client) sendMessage(msg WebSocketMessage) error {
 		return err
 	}
 
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+
 	return c.conn.WriteMessage(websocket.TextMessage, data)
 }
 
@@ -144,17 +302,16 @@ func (c *Client) readMessages() {
 
 			_, message, err := conn.ReadMessage()
 			if err != nil {
-				log.Errorf("Error reading message: %v", err)
-
-				log.Info("Attempting to reconnect...")
-				if err := c.reconnect(); err != nil {
-					log.Errorf("Failed to reconnect: %v", err)
-					time.Sleep(5 * time.Second)
-					continue
+				if c.ctx.Err() != nil {
+					return
 				}
-				continue
+				log.Errorf("Error reading message: %v", err)
+				c.emitConnection("disconnect")
+				c.reconnectLoop()
+				return
 			}
 
+			c.resetReconnectDelay()
 			c.handleMessage(message)
 		}
 	}
@@ -168,7 +325,9 @@ func (c *Client) reconnect() error {
 	}
 	c.mu.Unlock()
 
-	time.Sleep(2 * time.Second)
+	c.subsMu.Lock()
+	c.pending = make(map[int]pendingSubscription)
+	c.subsMu.Unlock()
 
 	return c.Connect()
 }
@@ -176,19 +335,10 @@ func (c *Client) reconnect() error {
 func (c *Client) handleMessage(data []byte) {
 	log.Debugf("Raw message received: %s", string(data))
 
-	var streamData map[string]interface{}
-	if err := json.Unmarshal(data, &streamData); err == nil {
-		if stream, ok := streamData["stream"].(string); ok {
-			log.Infof("Stream data received: %s", stream)
-			if dataBytes, ok := streamData["data"].(json.RawMessage); ok {
-				c.handleStreamData(stream, dataBytes)
-			} else {
-				if dataBytes, err := json.Marshal(streamData["data"]); err == nil {
-					c.handleStreamData(stream, dataBytes)
-				}
-			}
-			return
-		}
+	var push pushMessage
+	if err := json.Unmarshal(data, &push); err == nil && push.Channel != "" {
+		c.handlePush(push)
+		return
 	}
 
 	var msg WebSocketMessage
@@ -197,66 +347,68 @@ func (c *Client) handleMessage(data []byte) {
 		return
 	}
 
-	if msg.ID > 0 {
-		log.Infof("Subscription response: %+v", msg)
+	if msg.ID > 0 || msg.Msg != "" {
+		c.handleSubscriptionAck(msg)
 		return
 	}
 
 	log.Debugf("Other message: %+v", msg)
 }
 
-func (c *Client) handleStreamData(stream string, data json.RawMessage) {
-	if len(data) == 0 {
-		log.Debugf("Empty stream data for: %s", stream)
+func (c *Client) handlePush(push pushMessage) {
+	if len(push.Data) == 0 {
+		log.Debugf("Empty push data for: %s", push.Channel)
 		return
 	}
 
-	log.Infof("Processing stream data: %s, data length: %d", stream, len(data))
-
-	if stream == "spot@public.deals.v3.api" {
-		var trades []TradeData
-		if err := json.Unmarshal(data, &trades); err != nil {
+	switch {
+	case strings.HasPrefix(push.Channel, tradesStream):
+		var deals dealsPush
+		if err := json.Unmarshal(push.Data, &deals); err != nil {
 			log.Errorf("Error unmarshaling trade data: %v", err)
 			return
 		}
 
-		c.mu.RLock()
-		handlers := c.handlers["trade"]
-		c.mu.RUnlock()
-
-		for _, trade := range trades {
-			for _, handler := range handlers {
-				handler(trade)
-			}
+		for _, deal := range deals.Deals {
+			c.emit("trade", TradeData{
+				Symbol:    push.Symbol,
+				Price:     deal.Price,
+				Quantity:  deal.Quantity,
+				Timestamp: deal.Time,
+				IsBuyer:   deal.Side == dealSideBuy,
+			})
 		}
-		return
-	}
 
-	if stream == "spot@public.ticker.v3.api" {
-		var tickers []TickerData
-		if err := json.Unmarshal(data, &tickers); err != nil {
+	case strings.HasPrefix(push.Channel, tickersStream):
+		var ticker tickerPush
+		if err := json.Unmarshal(push.Data, &ticker); err != nil {
 			log.Errorf("Error unmarshaling ticker data: %v", err)
 			return
 		}
 
-		c.mu.RLock()
-		handlers := c.handlers["ticker"]
-		c.mu.RUnlock()
-
-		for _, ticker := range tickers {
-			for _, handler := range handlers {
-				handler(ticker)
-			}
+		symbol := ticker.Symbol
+		if symbol == "" {
+			symbol = push.Symbol
 		}
-		return
+		c.emit("ticker", TickerData{
+			Symbol:      symbol,
+			Price:       ticker.Price,
+			QuoteVolume: ticker.QuoteVolume,
+			Timestamp:   push.Time,
+		})
+
+	default:
+		log.Debugf("Unhandled push channel: %s", push.Channel)
 	}
 }
 
+func (c *Client) IsMock() bool {
+	return c.mock
+}
+
 func (c *Client) GetSpotSymbols() ([]string, error) {
-	return []string{
-		"BTCUSDT", "ETHUSDT", "BNBUSDT", "ADAUSDT", "SOLUSDT",
-		"XRPUSDT", "DOTUSDT", "DOGEUSDT", "AVAXUSDT", "MATICUSDT",
-		"LINKUSDT", "LTCUSDT", "UNIUSDT", "ATOMUSDT", "ETCUSDT",
-		"FILUSDT", "TRXUSDT", "XLMUSDT", "VETUSDT", "ALGOUSDT",
-	}, nil
+	if !c.mock {
+		return nil, fmt.Errorf("spot symbols are listed by the REST exchangeInfo endpoint")
+	}
+	return append([]string(nil), mockSymbols...), nil
 }
@@ -0,0 +1,40 @@
+package mexc
+
+import (
+	"testing"
+)
+
+func TestHandleMessagePushFrames(t *testing.T) {
+	c := NewClient(nil, nil)
+
+	var trades []TradeData
+	var tickers []TickerData
+	c.OnTrade(func(data interface{}) { trades = append(trades, data.(TradeData)) })
+	c.OnTicker(func(data interface{}) { tickers = append(tickers, data.(TickerData)) })
+
+	c.handleMessage([]byte(`{"c":"spot@public.deals.v3.api@BTCUSDT","d":{"deals":[` +
+		`{"S":1,"p":"27502.41","t":1679988040823,"v":"0.000363"},` +
+		`{"S":2,"p":"27502.40","t":1679988040824,"v":"0.1"}],` +
+		`"e":"spot@public.deals.v3.api"},"s":"BTCUSDT","t":1679988040825}`))
+	c.handleMessage([]byte(`{"c":"spot@public.ticker.v3.api@ETHUSDT","d":{"s":"ETHUSDT","p":"1820.5","q":"1523400.12"},` +
+		`"s":"ETHUSDT","t":1679988040900}`))
+
+	if len(trades) != 2 {
+		t.Fatalf("got %d trades, want 2", len(trades))
+	}
+	want := TradeData{Symbol: "BTCUSDT", Price: "27502.41", Quantity: "0.000363", Timestamp: 1679988040823, IsBuyer: true}
+	if trades[0] != want {
+		t.Errorf("trade = %+v, want %+v", trades[0], want)
+	}
+	if trades[1].IsBuyer {
+		t.Errorf("sell deal reported as buy: %+v", trades[1])
+	}
+
+	if len(tickers) != 1 {
+		t.Fatalf("got %d tickers, want 1", len(tickers))
+	}
+	wantTicker := TickerData{Symbol: "ETHUSDT", Price: "1820.5", QuoteVolume: "1523400.12", Timestamp: 1679988040900}
+	if tickers[0] != wantTicker {
+		t.Errorf("ticker = %+v, want %+v", tickers[0], wantTicker)
+	}
+}
@@ -1,10 +1,16 @@
 package mexc
 
 import (
+	"bytes"
+	"compress/gzip"
+	"crypto/tls"
 	"encoding/json"
 	"fmt"
 	"io"
+	"math"
 	"net/http"
+	"strconv"
+	"strings"
 	"time"
 
 	log "github.com/sirupsen/logrus"
@@ -13,6 +19,8 @@ import (
 type RESTClient struct {
 	baseURL    string
 	httpClient *http.Client
+	userAgent  string
+	headers    map[string]string
 }
 
 type TickerResponse struct {
@@ -20,12 +28,71 @@ type TickerResponse struct {
 	Price  string `json:"price"`
 }
 
+type tickerList []TickerResponse
+
+func (l *tickerList) UnmarshalJSON(data []byte) error {
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '{' {
+		var ticker TickerResponse
+		if err := json.Unmarshal(trimmed, &ticker); err != nil {
+			return err
+		}
+		*l = tickerList{ticker}
+		return nil
+	}
+
+	var tickers []TickerResponse
+	if err := json.Unmarshal(data, &tickers); err != nil {
+		return err
+	}
+	*l = tickers
+	return nil
+}
+
 type TradeResponse struct {
-	Symbol    string `json:"symbol"`
-	Price     string `json:"price"`
-	Qty       string `json:"qty"`
-	Time      int64  `json:"time"`
-	IsBuyerMaker bool `json:"isBuyerMaker"`
+	Symbol       string `json:"symbol"`
+	Price        string `json:"price"`
+	Qty          string `json:"qty"`
+	Time         int64  `json:"time"`
+	IsBuyerMaker bool   `json:"isBuyerMaker"`
+}
+
+type Kline struct {
+	OpenTime  time.Time
+	Open      float64
+	Close     float64
+	CloseTime time.Time
+}
+
+func (k *Kline) UnmarshalJSON(data []byte) error {
+	var fields []json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+	if len(fields) < 7 {
+		return fmt.Errorf("свеча содержит %d полей, ожидалось не меньше 7", len(fields))
+	}
+
+	var openTime, closeTime int64
+	var openPrice, closePrice string
+	for i, target := range []interface{}{&openTime, &openPrice, nil, nil, &closePrice, nil, &closeTime} {
+		if target == nil {
+			continue
+		}
+		if err := json.Unmarshal(fields[i], target); err != nil {
+			return err
+		}
+	}
+
+	var err error
+	if k.Open, err = strconv.ParseFloat(openPrice, 64); err != nil {
+		return err
+	}
+	if k.Close, err = strconv.ParseFloat(closePrice, 64); err != nil {
+		return err
+	}
+	k.OpenTime = time.UnixMilli(openTime)
+	k.CloseTime = time.UnixMilli(closeTime)
+	return nil
 }
 
 type ExchangeInfoResponse struct {
@@ -33,115 +100,211 @@ type ExchangeInfoResponse struct {
 }
 
 type SymbolInfo struct {
-	Symbol string `json:"symbol"`
-	Status string `json:"status"`
+	Symbol         string         `json:"symbol"`
+	Status         string         `json:"status"`
+	BaseAsset      string         `json:"baseAsset"`
+	QuoteAsset     string         `json:"quoteAsset"`
+	QuotePrecision int            `json:"quotePrecision"`
+	Filters        []SymbolFilter `json:"filters"`
+}
+
+type SymbolFilter struct {
+	FilterType string `json:"filterType"`
+	TickSize   string `json:"tickSize"`
+}
+
+// Tradable reports whether the pair is open for spot trading. MEXC has
+// reported the status as "1", "ENABLED" and "TRADING" over time.
+func (s SymbolInfo) Tradable() bool {
+	switch strings.ToUpper(s.Status) {
+	case "1", "ENABLED", "TRADING":
+		return true
+	}
+	return false
 }
 
-func NewRESTClient() *RESTClient {
+func (s SymbolInfo) TickSize() float64 {
+	for _, filter := range s.Filters {
+		if filter.FilterType != "PRICE_FILTER" {
+			continue
+		}
+		if tick, err := strconv.ParseFloat(filter.TickSize, 64); err == nil && tick > 0 {
+			return tick
+		}
+	}
+
+	if s.QuotePrecision > 0 {
+		return math.Pow10(-s.QuotePrecision)
+	}
+	return 0
+}
+
+func NewRESTClient(tlsConfig *tls.Config, userAgent string, headers map[string]string) *RESTClient {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = tlsConfig
+
 	return &RESTClient{
 		baseURL: "https://api.mexc.com",
 		httpClient: &http.Client{
-			Timeout: 10 * time.Second,
+			Timeout:   10 * time.Second,
+			Transport: transport,
 		},
+		userAgent: userAgent,
+		headers:   headers,
 	}
 }
 
-func (c *RESTClient) GetAllTickers() ([]TickerResponse, error) {
-	url := fmt.Sprintf("%s/api/v3/ticker/price", c.baseURL)
-	
-	resp, err := c.httpClient.Get(url)
+type APIError struct {
+	StatusCode int
+	Code       int    `json:"code"`
+	Msg        string `json:"msg"`
+}
+
+func (e *APIError) Error() string {
+	if e.Msg == "" {
+		return fmt.Sprintf("HTTP ошибка: %d", e.StatusCode)
+	}
+	return fmt.Sprintf("HTTP ошибка: %d, код MEXC %d: %s", e.StatusCode, e.Code, e.Msg)
+}
+
+func (c *RESTClient) get(url string, out interface{}) error {
+	req, err := http.NewRequest(http.MethodGet, url, nil)
 	if err != nil {
-		return nil, fmt.Errorf("ошибка запроса: %v", err)
+		return fmt.Errorf("ошибка создания запроса: %v", err)
+	}
+
+	if c.userAgent != "" {
+		req.Header.Set("User-Agent", c.userAgent)
+	}
+	for name, value := range c.headers {
+		req.Header.Set(name, value)
+	}
+	req.Header.Set("Accept-Encoding", "gzip")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("ошибка запроса: %v", err)
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("HTTP ошибка: %d", resp.StatusCode)
+	var reader io.Reader = resp.Body
+	if resp.Header.Get("Content-Encoding") == "gzip" {
+		gz, err := gzip.NewReader(resp.Body)
+		if err != nil {
+			return fmt.Errorf("ошибка распаковки ответа: %v", err)
+		}
+		defer gz.Close()
+		reader = gz
 	}
 
-	body, err := io.ReadAll(resp.Body)
+	body, err := io.ReadAll(reader)
 	if err != nil {
-		return nil, fmt.Errorf("ошибка чтения ответа: %v", err)
+		return fmt.Errorf("ошибка чтения ответа: %v", err)
 	}
 
-	var tickers []TickerResponse
-	if err := json.Unmarshal(body, &tickers); err != nil {
-		return nil, fmt.Errorf("ошибка парсинга JSON: %v", err)
+	if resp.StatusCode != http.StatusOK {
+		apiErr := &APIError{StatusCode: resp.StatusCode}
+		if err := json.Unmarshal(body, apiErr); err != nil {
+			log.Debugf("Не удалось разобрать тело ошибки MEXC: %s", string(body))
+		}
+		return apiErr
 	}
 
-	return tickers, nil
+	if err := json.Unmarshal(body, out); err != nil {
+		return fmt.Errorf("ошибка парсинга JSON: %v", err)
+	}
+
+	return nil
 }
 
-func (c *RESTClient) GetRecentTrades(symbol string) ([]TradeResponse, error) {
-	url := fmt.Sprintf("%s/api/v3/trades?symbol=%s&limit=100", c.baseURL, symbol)
-	
-	resp, err := c.httpClient.Get(url)
-	if err != nil {
-		return nil, fmt.Errorf("ошибка запроса: %v", err)
+func (c *RESTClient) GetAllTickers() ([]TickerResponse, error) {
+	url := fmt.Sprintf("%s/api/v3/ticker/price", c.baseURL)
+
+	var tickers tickerList
+	if err := c.get(url, &tickers); err != nil {
+		return nil, err
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("HTTP ошибка: %d", resp.StatusCode)
+	return tickers, nil
+}
+
+func (c *RESTClient) GetTicker(symbol string) (*TickerResponse, error) {
+	url := fmt.Sprintf("%s/api/v3/ticker/price?symbol=%s", c.baseURL, symbol)
+
+	var tickers tickerList
+	if err := c.get(url, &tickers); err != nil {
+		return nil, err
 	}
 
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("ошибка чтения ответа: %v", err)
+	for _, ticker := range tickers {
+		if ticker.Symbol == symbol {
+			return &ticker, nil
+		}
 	}
+	return nil, fmt.Errorf("тикер %s не найден в ответе", symbol)
+}
+
+func (c *RESTClient) GetRecentTrades(symbol string) ([]TradeResponse, error) {
+	url := fmt.Sprintf("%s/api/v3/trades?symbol=%s&limit=100", c.baseURL, symbol)
 
 	var trades []TradeResponse
-	if err := json.Unmarshal(body, &trades); err != nil {
-		return nil, fmt.Errorf("ошибка парсинга JSON: %v", err)
+	if err := c.get(url, &trades); err != nil {
+		return nil, err
 	}
 
 	return trades, nil
 }
 
+func (c *RESTClient) GetKlines(symbol, interval string, limit int) ([]Kline, error) {
+	url := fmt.Sprintf("%s/api/v3/klines?symbol=%s&interval=%s&limit=%d", c.baseURL, symbol, interval, limit)
+
+	var klines []Kline
+	if err := c.get(url, &klines); err != nil {
+		return nil, err
+	}
+
+	return klines, nil
+}
+
 func (c *RESTClient) GetExchangeInfo() (*ExchangeInfoResponse, error) {
 	url := fmt.Sprintf("%s/api/v3/exchangeInfo", c.baseURL)
-	
-	resp, err := c.httpClient.Get(url)
-	if err != nil {
-		return nil, fmt.Errorf("ошибка запроса: %v", err)
-	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("HTTP ошибка: %d", resp.StatusCode)
+	var exchangeInfo ExchangeInfoResponse
+	if err := c.get(url, &exchangeInfo); err != nil {
+		return nil, err
 	}
 
-	body, err := io.ReadAll(resp.Body)
+	return &exchangeInfo, nil
+}
+
+func (c *RESTClient) GetTickSizes() (map[string]float64, error) {
+	exchangeInfo, err := c.GetExchangeInfo()
 	if err != nil {
-		return nil, fmt.Errorf("ошибка чтения ответа: %v", err)
+		return nil, err
 	}
 
-	var exchangeInfo ExchangeInfoResponse
-	if err := json.Unmarshal(body, &exchangeInfo); err != nil {
-		return nil, fmt.Errorf("ошибка парсинга JSON: %v", err)
+	tickSizes := make(map[string]float64, len(exchangeInfo.Symbols))
+	for _, symbol := range exchangeInfo.Symbols {
+		if tick := symbol.TickSize(); tick > 0 {
+			tickSizes[symbol.Symbol] = tick
+		}
 	}
-
-	return &exchangeInfo, nil
+	return tickSizes, nil
 }
 
-func (c *RESTClient) GetActiveSymbols() ([]string, error) {
+func (c *RESTClient) GetActiveSymbols() ([]SymbolInfo, error) {
 	exchangeInfo, err := c.GetExchangeInfo()
 	if err != nil {
 		return nil, err
 	}
 
-	var activeSymbols []string
+	var activeSymbols []SymbolInfo
 	for _, symbol := range exchangeInfo.Symbols {
-		if symbol.Status == "TRADING" {
-			activeSymbols = append(activeSymbols, symbol.Symbol)
+		if symbol.Tradable() {
+			activeSymbols = append(activeSymbols, symbol)
 		}
 	}
 
 	log.Infof("Найдено %d активных торговых пар", len(activeSymbols))
 	return activeSymbols, nil
 }
-
-
-
-
-
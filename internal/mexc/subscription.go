@@ -0,0 +1,208 @@
+package mexc
+
+import (
+	"fmt"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	maxSubscribeAttempts      = 3
+	maxStreamsPerSubscription = 30
+
+	MaxStreamsPerConnection = 30
+
+	tradesStream  = "spot@public.deals.v3.api"
+	tickersStream = "spot@public.ticker.v3.api"
+)
+
+type pendingSubscription struct {
+	params      []string
+	attempts    int
+	unsubscribe bool
+}
+
+func (c *Client) subscribeStreams(stream string, symbols []string) error {
+	if c.mock || len(symbols) == 0 {
+		return nil
+	}
+
+	c.subsMu.Lock()
+	free := MaxStreamsPerConnection - len(c.streams)
+	var params, overflow []string
+	for _, symbol := range symbols {
+		param := stream + "@" + symbol
+		switch {
+		case c.streams[param]:
+		case len(params) < free:
+			params = append(params, param)
+		default:
+			overflow = append(overflow, symbol)
+		}
+	}
+	c.subsMu.Unlock()
+
+	if len(params) > 0 {
+		if err := c.subscribe(params); err != nil {
+			return fmt.Errorf("failed to subscribe to %s: %w", stream, err)
+		}
+		log.Infof("Subscribed to %s for %d symbols", stream, len(params))
+	}
+
+	if len(overflow) > 0 {
+		return fmt.Errorf("%d %s streams not subscribed, MEXC allows %d streams per connection: %s",
+			len(overflow), stream, MaxStreamsPerConnection, strings.Join(overflow, ", "))
+	}
+	return nil
+}
+
+func (c *Client) unsubscribeStreams(stream string, symbols []string) error {
+	if c.mock || len(symbols) == 0 {
+		return nil
+	}
+
+	params := make([]string, 0, len(symbols))
+	c.subsMu.Lock()
+	for _, symbol := range symbols {
+		param := stream + "@" + symbol
+		if c.streams[param] {
+			params = append(params, param)
+			delete(c.streams, param)
+		}
+	}
+	if len(params) == 0 {
+		c.subsMu.Unlock()
+		return nil
+	}
+	c.nextID++
+	id := c.nextID
+	c.pending[id] = pendingSubscription{params: params, unsubscribe: true}
+	c.subsMu.Unlock()
+
+	err := c.sendMessage(WebSocketMessage{
+		Method: "UNSUBSCRIPTION",
+		Params: params,
+		ID:     id,
+	})
+	if err != nil {
+		c.subsMu.Lock()
+		delete(c.pending, id)
+		c.subsMu.Unlock()
+		return fmt.Errorf("failed to unsubscribe from %s: %w", stream, err)
+	}
+
+	log.Infof("Unsubscribed from %s for %d symbols", stream, len(params))
+	return nil
+}
+
+func (c *Client) subscribe(params []string) error {
+	c.subsMu.Lock()
+	for _, stream := range params {
+		c.streams[stream] = true
+	}
+	c.subsMu.Unlock()
+
+	return c.subscribeAttempt(params, 1)
+}
+
+func (c *Client) resubscribe() {
+	c.subsMu.Lock()
+	streams := make([]string, 0, len(c.streams))
+	for stream := range c.streams {
+		streams = append(streams, stream)
+	}
+	c.subsMu.Unlock()
+
+	if len(streams) == 0 {
+		return
+	}
+
+	log.Infof("Restoring %d stream subscriptions after reconnect", len(streams))
+
+	for start := 0; start < len(streams); start += maxStreamsPerSubscription {
+		end := start + maxStreamsPerSubscription
+		if end > len(streams) {
+			end = len(streams)
+		}
+		if err := c.subscribeAttempt(streams[start:end], 1); err != nil {
+			log.Errorf("Failed to restore subscriptions: %v", err)
+		}
+	}
+}
+
+func (c *Client) subscribeAttempt(params []string, attempt int) error {
+	c.subsMu.Lock()
+	c.nextID++
+	id := c.nextID
+	c.pending[id] = pendingSubscription{params: params, attempts: attempt}
+	c.subsMu.Unlock()
+
+	err := c.sendMessage(WebSocketMessage{
+		Method: "SUBSCRIPTION",
+		Params: params,
+		ID:     id,
+	})
+	if err != nil {
+		c.subsMu.Lock()
+		delete(c.pending, id)
+		c.subsMu.Unlock()
+		return err
+	}
+
+	log.Debugf("Subscription %d sent: %d streams (attempt %d)", id, len(params), attempt)
+	return nil
+}
+
+func (c *Client) handleSubscriptionAck(msg WebSocketMessage) {
+	c.subsMu.Lock()
+	pending, ok := c.pending[msg.ID]
+	delete(c.pending, msg.ID)
+	c.subsMu.Unlock()
+
+	if !ok {
+		log.Infof("Subscription response for unknown request %d: code=%d msg=%s", msg.ID, msg.Code, msg.Msg)
+		return
+	}
+	if pending.unsubscribe {
+		log.Debugf("Unsubscription %d acknowledged: code=%d msg=%s", msg.ID, msg.Code, msg.Msg)
+		return
+	}
+
+	accepted := make(map[string]bool)
+	if msg.Code == 0 {
+		for _, stream := range strings.Split(msg.Msg, ",") {
+			accepted[strings.TrimSpace(stream)] = true
+		}
+	}
+
+	var rejected []string
+	for _, stream := range pending.params {
+		if !accepted[stream] {
+			rejected = append(rejected, stream)
+		}
+	}
+
+	if len(rejected) == 0 {
+		log.Infof("Subscription %d confirmed: %d streams", msg.ID, len(pending.params))
+		return
+	}
+
+	log.Warnf("Subscription %d rejected %d of %d streams (code=%d): %s",
+		msg.ID, len(rejected), len(pending.params), msg.Code, msg.Msg)
+
+	if pending.attempts >= maxSubscribeAttempts {
+		log.Errorf("Giving up on %d streams after %d attempts: %s",
+			len(rejected), pending.attempts, strings.Join(rejected, ", "))
+		c.subsMu.Lock()
+		for _, stream := range rejected {
+			delete(c.streams, stream)
+		}
+		c.subsMu.Unlock()
+		return
+	}
+
+	if err := c.subscribeAttempt(rejected, pending.attempts+1); err != nil {
+		log.Errorf("Failed to retry subscription: %v", err)
+	}
+}
@@ -0,0 +1,192 @@
+package mexc
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+type fakeServer struct {
+	*httptest.Server
+
+	mu     sync.Mutex
+	frames []WebSocketMessage
+	conns  []*websocket.Conn
+}
+
+func newFakeServer(t *testing.T) *fakeServer {
+	t.Helper()
+
+	s := &fakeServer{}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := (&websocket.Upgrader{}).Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		s.mu.Lock()
+		s.conns = append(s.conns, conn)
+		s.mu.Unlock()
+
+		for {
+			_, data, err := conn.ReadMessage()
+			if err != nil {
+				return
+			}
+			var msg WebSocketMessage
+			if err := json.Unmarshal(data, &msg); err != nil {
+				continue
+			}
+			s.mu.Lock()
+			s.frames = append(s.frames, msg)
+			s.mu.Unlock()
+
+			ack := fmt.Sprintf(`{"id":%d,"code":0,"msg":"%s"}`, msg.ID, strings.Join(msg.Params, ","))
+			conn.WriteMessage(websocket.TextMessage, []byte(ack))
+		}
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func (s *fakeServer) url() string {
+	return "ws" + strings.TrimPrefix(s.URL, "http")
+}
+
+func (s *fakeServer) subscribed() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var params []string
+	for _, frame := range s.frames {
+		if frame.Method == "SUBSCRIPTION" {
+			params = append(params, frame.Params...)
+		}
+	}
+	return params
+}
+
+func (s *fakeServer) dropConnections() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, conn := range s.conns {
+		conn.Close()
+	}
+	s.conns = nil
+	s.frames = nil
+}
+
+func waitFor(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestSubscribeStreamsRespectsConnectionLimit(t *testing.T) {
+	server := newFakeServer(t)
+	c := NewClient([]string{server.url()}, nil)
+	if err := c.Connect(); err != nil {
+		t.Fatalf("Connect() error = %v", err)
+	}
+	defer c.Disconnect()
+
+	var symbols []string
+	for i := 0; i < 20; i++ {
+		symbols = append(symbols, fmt.Sprintf("COIN%dUSDT", i))
+	}
+
+	if err := c.SubscribeToTrades(symbols); err != nil {
+		t.Fatalf("SubscribeToTrades() error = %v", err)
+	}
+	if err := c.SubscribeToTickers(symbols); err == nil {
+		t.Errorf("SubscribeToTickers() over the limit returned no error")
+	}
+
+	waitFor(t, "subscriptions", func() bool { return len(server.subscribed()) == MaxStreamsPerConnection })
+	if err := c.SubscribeToTrades(symbols[:1]); err != nil {
+		t.Errorf("re-subscribing an existing stream returned %v", err)
+	}
+}
+
+func TestResubscribeAfterReconnect(t *testing.T) {
+	server := newFakeServer(t)
+	c := NewClient([]string{server.url()}, nil)
+	c.ReconnectDelay = 10 * time.Millisecond
+
+	reconnected := make(chan struct{}, 1)
+	c.OnReconnect(func() { reconnected <- struct{}{} })
+
+	if err := c.Connect(); err != nil {
+		t.Fatalf("Connect() error = %v", err)
+	}
+	defer c.Disconnect()
+
+	if err := c.SubscribeToTrades([]string{"BTCUSDT", "ETHUSDT"}); err != nil {
+		t.Fatalf("SubscribeToTrades() error = %v", err)
+	}
+	waitFor(t, "initial subscription", func() bool { return len(server.subscribed()) == 2 })
+
+	server.dropConnections()
+
+	select {
+	case <-reconnected:
+	case <-time.After(5 * time.Second):
+		t.Fatal("client did not reconnect")
+	}
+
+	waitFor(t, "restored subscription", func() bool { return len(server.subscribed()) == 2 })
+	restored := strings.Join(server.subscribed(), ",")
+	for _, stream := range []string{"spot@public.deals.v3.api@BTCUSDT", "spot@public.deals.v3.api@ETHUSDT"} {
+		if !strings.Contains(restored, stream) {
+			t.Errorf("stream %s not restored after reconnect, got %s", stream, restored)
+		}
+	}
+}
+
+func TestUnsubscribeFreesStreams(t *testing.T) {
+	server := newFakeServer(t)
+	c := NewClient([]string{server.url()}, nil)
+	if err := c.Connect(); err != nil {
+		t.Fatalf("Connect() error = %v", err)
+	}
+	defer c.Disconnect()
+
+	var symbols []string
+	for i := 0; i < MaxStreamsPerConnection; i++ {
+		symbols = append(symbols, fmt.Sprintf("COIN%dUSDT", i))
+	}
+	if err := c.SubscribeToTrades(symbols); err != nil {
+		t.Fatalf("SubscribeToTrades() error = %v", err)
+	}
+
+	if err := c.UnsubscribeFromTrades(symbols[:1]); err != nil {
+		t.Fatalf("UnsubscribeFromTrades() error = %v", err)
+	}
+	if err := c.SubscribeToTickers([]string{"NEWUSDT"}); err != nil {
+		t.Errorf("SubscribeToTickers() after unsubscribing returned %v", err)
+	}
+
+	waitFor(t, "unsubscription", func() bool {
+		server.mu.Lock()
+		defer server.mu.Unlock()
+		for _, frame := range server.frames {
+			if frame.Method == "UNSUBSCRIPTION" {
+				return len(frame.Params) == 1 && frame.Params[0] == tradesStream+"@COIN0USDT"
+			}
+		}
+		return false
+	})
+}
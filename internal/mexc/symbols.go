@@ -0,0 +1,64 @@
+package mexc
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+var defaultQuoteAssets = []string{"USDT", "USDC", "USDE", "BTC", "ETH", "EUR"}
+
+func TradeURL(base, quote string) string {
+	return fmt.Sprintf("https://www.mexc.com/exchange/%s_%s", base, quote)
+}
+
+type SymbolSplitter struct {
+	assets map[string][2]string
+	quotes []string
+}
+
+func NewSymbolSplitter(symbols []SymbolInfo) *SymbolSplitter {
+	s := &SymbolSplitter{assets: make(map[string][2]string, len(symbols))}
+
+	quotes := make(map[string]bool)
+	for _, quote := range defaultQuoteAssets {
+		quotes[quote] = true
+	}
+	for _, info := range symbols {
+		if info.BaseAsset == "" || info.QuoteAsset == "" {
+			continue
+		}
+		s.assets[info.Symbol] = [2]string{info.BaseAsset, info.QuoteAsset}
+		quotes[info.QuoteAsset] = true
+	}
+
+	for quote := range quotes {
+		s.quotes = append(s.quotes, quote)
+	}
+	sort.Slice(s.quotes, func(i, j int) bool {
+		if len(s.quotes[i]) != len(s.quotes[j]) {
+			return len(s.quotes[i]) > len(s.quotes[j])
+		}
+		return s.quotes[i] < s.quotes[j]
+	})
+	return s
+}
+
+func (s *SymbolSplitter) Split(symbol string) (string, string, bool) {
+	if assets, ok := s.assets[symbol]; ok {
+		return assets[0], assets[1], true
+	}
+
+	for _, r := range symbol {
+		if (r < 'A' || r > 'Z') && (r < '0' || r > '9') {
+			return symbol, "", false
+		}
+	}
+
+	for _, quote := range s.quotes {
+		if len(symbol) > len(quote) && strings.HasSuffix(symbol, quote) {
+			return strings.TrimSuffix(symbol, quote), quote, true
+		}
+	}
+	return symbol, "", false
+}
@@ -0,0 +1,36 @@
+package mexc
+
+import "testing"
+
+func TestSymbolSplitterSplit(t *testing.T) {
+	splitter := NewSymbolSplitter([]SymbolInfo{
+		{Symbol: "USDCUSDT", BaseAsset: "USDC", QuoteAsset: "USDT"},
+		{Symbol: "ETHBTC", BaseAsset: "ETH", QuoteAsset: "BTC"},
+		{Symbol: "PEPEUSDE", BaseAsset: "PEPEU", QuoteAsset: "SDE"},
+		{Symbol: "XAUTTRY", BaseAsset: "XAUT", QuoteAsset: "TRY"},
+	})
+
+	tests := []struct {
+		symbol string
+		base   string
+		quote  string
+		ok     bool
+	}{
+		{symbol: "USDCUSDT", base: "USDC", quote: "USDT", ok: true},
+		{symbol: "ETHBTC", base: "ETH", quote: "BTC", ok: true},
+		{symbol: "PEPEUSDE", base: "PEPEU", quote: "SDE", ok: true},
+		{symbol: "DOGEUSDE", base: "DOGE", quote: "USDE", ok: true},
+		{symbol: "SOLTRY", base: "SOL", quote: "TRY", ok: true},
+		{symbol: "BTCUSDC", base: "BTC", quote: "USDC", ok: true},
+		{symbol: "FOOXYZ", base: "FOOXYZ", ok: false},
+		{symbol: "USDT", base: "USDT", ok: false},
+		{symbol: "BTC/USDT", base: "BTC/USDT", ok: false},
+	}
+
+	for _, tt := range tests {
+		base, quote, ok := splitter.Split(tt.symbol)
+		if base != tt.base || quote != tt.quote || ok != tt.ok {
+			t.Errorf("Split(%q) = %q, %q, %t; want %q, %q, %t", tt.symbol, base, quote, ok, tt.base, tt.quote, tt.ok)
+		}
+	}
+}
@@ -0,0 +1,726 @@
+package monitor
+
+import (
+	"fmt"
+	"html"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"mexc-monitor/internal/database"
+	"mexc-monitor/internal/telegram"
+
+	log "github.com/sirupsen/logrus"
+)
+
+type symbolSnapshot struct {
+	symbol    string
+	history   []*PriceData
+	volume    VolumeData
+	hasVolume bool
+	pinned    bool
+	lastAlert time.Time
+	ema       float64
+	hasEMA    bool
+	anchor    anchorPrice
+	hasAnchor bool
+}
+
+type analysisParams struct {
+	settings   *database.Settings
+	now        time.Time
+	windowEnd  time.Time
+	cutoffTime time.Time
+	cooldowns  map[string]time.Duration
+}
+
+type alertCandidate struct {
+	symbol      string
+	priceChange float64
+	price       float64
+	volume      int
+}
+
+func (m *Monitor) analyzeData() int {
+	log.Debug("Starting data analysis...")
+
+	settings, err := m.settings()
+	if err != nil {
+		log.Errorf("Failed to get settings: %v", err)
+		return 0
+	}
+
+	now := time.Now()
+	settings = m.calmSettings(settings, now)
+	interval := time.Duration(settings.TimeInterval) * time.Second
+
+	if m.inMaintenance(now) {
+		log.Debug("Skipping analysis: maintenance window is active")
+		return 0
+	}
+
+	log.Debugf("Analysis settings: time_interval=%d, price_change=%.2f%%, min_volume=%d",
+		settings.TimeInterval, settings.PriceChange, settings.MinVolume)
+
+	m.mu.Lock()
+	windowEnd := now
+	if m.cfg.Monitoring.CompletedIntervals {
+		windowEnd = now.Truncate(interval)
+		if !windowEnd.After(m.lastWindowEnd) {
+			m.mu.Unlock()
+			log.Debugf("Interval ending at %s already analyzed", windowEnd.Format("15:04:05"))
+			return 0
+		}
+		m.lastWindowEnd = windowEnd
+	}
+	snapshots := m.snapshot(now)
+	m.mu.Unlock()
+
+	params := analysisParams{
+		settings:   settings,
+		now:        now,
+		windowEnd:  windowEnd,
+		cutoffTime: windowEnd.Add(-interval),
+		cooldowns:  m.symbolCooldowns(),
+	}
+
+	log.Debugf("Analyzing %d symbols", len(snapshots))
+
+	evaluations := m.evaluateAll(snapshots, params)
+	m.applyRelative(evaluations, settings)
+
+	var candidates []alertCandidate
+	for _, e := range evaluations {
+		if e.triggered() {
+			candidates = append(candidates, alertCandidate{
+				symbol:      e.symbol,
+				priceChange: e.priceChange,
+				price:       e.currentPrice,
+				volume:      e.volume,
+			})
+		}
+	}
+
+	sort.Slice(candidates, func(i, j int) bool {
+		return math.Abs(candidates[i].priceChange) > math.Abs(candidates[j].priceChange)
+	})
+
+	individual := candidates[:m.reserveAlerts(len(candidates), now)]
+	var overflow []telegram.SymbolChange
+	for _, candidate := range candidates[len(individual):] {
+		overflow = append(overflow, telegram.SymbolChange{Symbol: candidate.symbol, Change: candidate.priceChange})
+	}
+	overflow = append(overflow, m.trackSustainedMoves(evaluations, params)...)
+	overflow = append(overflow, m.trackAcceleration(evaluations, params)...)
+
+	for _, candidate := range individual {
+		log.Infof("Conditions met for %s! Sending alert...", candidate.symbol)
+		if err := m.bot.SendAlert(candidate.symbol, candidate.priceChange, candidate.volume, now); err != nil {
+			log.Errorf("Failed to send alert for %s: %v", candidate.symbol, err)
+		} else {
+			log.Infof("Alert sent for %s: %.2f%% change, $%d volume",
+				candidate.symbol, candidate.priceChange, candidate.volume)
+		}
+	}
+
+	m.sendOverflow(overflow, now)
+
+	if len(candidates) > 0 {
+		m.mu.Lock()
+		for _, candidate := range candidates {
+			delete(m.volumeData, candidate.symbol)
+			m.lastAlerts[candidate.symbol] = now
+			m.raiseAnchorTier(candidate.symbol, candidate.priceChange, params.settings)
+		}
+		m.mu.Unlock()
+
+		for _, candidate := range candidates {
+			if err := m.db.SetLastAlert(candidate.symbol, now); err != nil {
+				log.Errorf("Failed to persist alert cooldown for %s: %v", candidate.symbol, err)
+			}
+			m.recordAlert("change", candidate.symbol, candidate.priceChange, candidate.price, candidate.volume, params)
+		}
+	}
+
+	return len(candidates)
+}
+
+func (m *Monitor) AnalyzeNow() int {
+	return m.runAnalysis()
+}
+
+func (m *Monitor) runAnalysis() int {
+	m.analysisMu.Lock()
+	defer m.analysisMu.Unlock()
+
+	start := time.Now()
+	alerts := m.analyzeData()
+	m.recordAnalysisDuration(time.Since(start))
+	return alerts
+}
+
+// reserveAlerts takes up to wanted alerts from the max_alerts_per_minute
+// budget shared by every alert type and returns how many may be sent.
+func (m *Monitor) reserveAlerts(wanted int, now time.Time) int {
+	limit := m.cfg.Monitoring.MaxAlertsPerMinute
+	if limit <= 0 || wanted == 0 {
+		return wanted
+	}
+
+	m.budgetMu.Lock()
+	defer m.budgetMu.Unlock()
+
+	cutoff := now.Add(-time.Minute)
+	recent := m.sentAlerts[:0]
+	for _, t := range m.sentAlerts {
+		if t.After(cutoff) {
+			recent = append(recent, t)
+		}
+	}
+
+	budget := limit - len(recent)
+	if budget < 0 {
+		budget = 0
+	}
+	if budget > wanted {
+		budget = wanted
+	}
+	for i := 0; i < budget; i++ {
+		recent = append(recent, now)
+	}
+	m.sentAlerts = recent
+
+	return budget
+}
+
+func (m *Monitor) sendOverflow(overflow []telegram.SymbolChange, now time.Time) {
+	if len(overflow) == 0 {
+		return
+	}
+
+	log.Warnf("Alert cap reached: coalescing %d alerts into a summary", len(overflow))
+	if err := m.bot.SendAlertSummary(overflow, now); err != nil {
+		log.Errorf("Failed to send alert summary: %v", err)
+	}
+}
+
+func (m *Monitor) snapshot(now time.Time) []symbolSnapshot {
+	snapshots := make([]symbolSnapshot, 0, len(m.priceHistory))
+
+	for symbol, history := range m.priceHistory {
+		if len(history) == 0 {
+			log.Debugf("Skipping %s: no price history", symbol)
+			continue
+		}
+
+		if m.checkStaleness(symbol, history[len(history)-1].Timestamp, now) {
+			log.Debugf("Skipping %s: price feed is stale", symbol)
+			continue
+		}
+
+		snapshots = append(snapshots, m.snapshotSymbol(symbol, history))
+	}
+
+	return snapshots
+}
+
+func (m *Monitor) snapshotSymbol(symbol string, history []*PriceData) symbolSnapshot {
+	snap := symbolSnapshot{
+		symbol:    symbol,
+		history:   make([]*PriceData, len(history)),
+		pinned:    m.alwaysSymbols[symbol],
+		lastAlert: m.lastAlerts[symbol],
+	}
+	for i, priceData := range history {
+		point := *priceData
+		snap.history[i] = &point
+	}
+	if volData, exists := m.volumeData[symbol]; exists {
+		snap.volume = *volData
+		snap.hasVolume = true
+	}
+	snap.ema, snap.hasEMA = m.ema[symbol]
+	snap.anchor, snap.hasAnchor = m.anchors[symbol]
+	return snap
+}
+
+func (m *Monitor) evaluateAll(snapshots []symbolSnapshot, params analysisParams) []evaluation {
+	workers := m.cfg.Monitoring.AnalysisWorkers
+	if workers < 1 {
+		workers = 1
+	}
+	if workers > len(snapshots) {
+		workers = len(snapshots)
+	}
+
+	jobs := make(chan symbolSnapshot)
+	results := make(chan evaluation)
+
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for snap := range jobs {
+				results <- m.evaluate(snap, params)
+			}
+		}()
+	}
+
+	go func() {
+		for _, snap := range snapshots {
+			jobs <- snap
+		}
+		close(jobs)
+		wg.Wait()
+		close(results)
+	}()
+
+	var evaluations []evaluation
+	for e := range results {
+		evaluations = append(evaluations, e)
+	}
+	return evaluations
+}
+
+type sustainedMove struct {
+	direction int
+	cycles    int
+}
+
+type evaluation struct {
+	symbol        string
+	skip          telegram.SkipReason
+	currentPrice  float64
+	startPrice    float64
+	priceChange   float64
+	volume        int
+	pinned        bool
+	changeOK      bool
+	volumeOK      bool
+	volumeUnknown bool
+	priorChange   float64
+	recentChange  float64
+	baselineAge   time.Duration
+	baselineEMA   bool
+	anchored      bool
+	anchorTier    int
+}
+
+func (e *evaluation) triggered() bool {
+	return e.skip == telegram.SkipNone && e.changeOK && e.volumeOK
+}
+
+func (e *evaluation) checkChange(settings *database.Settings) {
+	e.changeOK = e.priceChange >= settings.PriceChange || e.priceChange <= -settings.PriceChange
+	if e.anchored && e.changeOK && anchorTier(e.priceChange, settings) <= e.anchorTier {
+		e.changeOK = false
+	}
+}
+
+func (e *evaluation) checkVolume(settings *database.Settings) {
+	e.volumeOK = e.pinned || e.volumeUnknown || e.volume >= settings.VolumeThreshold(e.priceChange)
+}
+
+func (e *evaluation) accelerating(threshold float64) bool {
+	if threshold <= 0 || e.recentChange*e.priorChange < 0 {
+		return false
+	}
+	return math.Abs(e.recentChange)-math.Abs(e.priorChange) >= threshold
+}
+
+func (m *Monitor) evaluate(snap symbolSnapshot, params analysisParams) evaluation {
+	symbol := snap.symbol
+	history := snap.history
+	settings := params.settings
+
+	e := evaluation{
+		symbol: symbol,
+		pinned: snap.pinned,
+	}
+
+	current := priceAt(history, params.windowEnd)
+	if current == nil {
+		log.Debugf("Skipping %s: no price before %s", symbol, params.windowEnd.Format("15:04:05"))
+		e.skip = telegram.SkipNoHistory
+		return e
+	}
+	e.currentPrice = current.Price
+	currentTime := current.Timestamp
+
+	log.Debugf("Analyzing %s: current price=%.6f, time=%s",
+		symbol, e.currentPrice, currentTime.Format("15:04:05"))
+
+	if snap.hasVolume {
+		e.volume = snap.volume.Volume
+	}
+
+	if start := priceAt(history, params.cutoffTime); start != nil {
+		e.startPrice = start.Price
+		e.baselineAge = currentTime.Sub(start.Timestamp)
+	} else {
+		e.startPrice = history[0].Price
+		e.baselineAge = currentTime.Sub(history[0].Timestamp)
+		m.tracef(symbol, "no price before window start, baseline is only %s old", e.baselineAge.Round(time.Millisecond))
+	}
+
+	if m.cfg.Monitoring.AccelerationThreshold > 0 {
+		e.priorChange, e.recentChange = subWindowChanges(history, params, e.currentPrice)
+	}
+
+	if snap.hasAnchor {
+		e.startPrice = snap.anchor.price
+		e.baselineAge = currentTime.Sub(snap.anchor.at)
+		e.anchored = true
+		e.anchorTier = snap.anchor.tier
+	} else if snap.hasEMA {
+		e.startPrice = snap.ema
+		e.baselineEMA = true
+	}
+
+	log.Debugf("Price analysis for %s: start=%.6f, current=%.6f",
+		symbol, e.startPrice, e.currentPrice)
+
+	if e.startPrice > 0 {
+		e.priceChange = ((e.currentPrice - e.startPrice) / e.startPrice) * 100
+	}
+
+	log.Debugf("Price change for %s: %.4f%%", symbol, e.priceChange)
+
+	e.checkChange(settings)
+	if e.anchored && !e.changeOK && anchorTier(e.priceChange, settings) > 0 {
+		m.tracef(symbol, "change from session open already alerted at %dx threshold", e.anchorTier)
+	}
+	minVolume := settings.VolumeThreshold(e.priceChange)
+	e.checkVolume(settings)
+
+	cooldown := time.Duration(m.cfg.Monitoring.AlertCooldown) * time.Second
+	if override, ok := params.cooldowns[symbol]; ok {
+		cooldown = override
+	}
+
+	if currentTime.Before(params.cutoffTime) {
+		log.Debugf("Skipping %s: price too old", symbol)
+		m.tracef(symbol, "skipped: latest price at %s is older than window", currentTime.Format("15:04:05"))
+		e.skip = telegram.SkipPriceTooOld
+		return e
+	}
+
+	// Blacklisting is checked before the cooldown: sustained tracking keeps
+	// counting cooled-down symbols and must not see blacklisted ones.
+	if blacklisted, err := m.db.IsBlacklisted(symbol); err != nil {
+		log.Errorf("Failed to check blacklist for %s: %v", symbol, err)
+		e.skip = telegram.SkipBlacklisted
+		return e
+	} else if blacklisted {
+		m.tracef(symbol, "skipped: blacklisted")
+		e.skip = telegram.SkipBlacklisted
+		return e
+	}
+
+	if cooldown > 0 && params.now.Sub(snap.lastAlert) < cooldown {
+		log.Debugf("Skipping %s: alert cooldown until %s", symbol, snap.lastAlert.Add(cooldown).Format("15:04:05"))
+		m.tracef(symbol, "skipped: cooldown until %s", snap.lastAlert.Add(cooldown).Format("15:04:05"))
+		e.skip = telegram.SkipCooldown
+		return e
+	}
+
+	if !snap.pinned && (!snap.hasVolume || snap.volume.Timestamp.Before(params.cutoffTime)) {
+		if m.cfg.Monitoring.MissingVolume != "price_only" {
+			m.tracef(symbol, "skipped: no fresh volume data")
+			e.skip = telegram.SkipNoVolume
+			return e
+		}
+		m.tracef(symbol, "volume unknown, evaluating price change alone")
+		e.volume = 0
+		e.volumeUnknown = true
+		e.volumeOK = true
+	}
+
+	log.Debugf("Checking conditions for %s: volume=%d (min=%d, pinned=%t), price_change=%.4f%% (threshold=%.2f%%)",
+		symbol, e.volume, minVolume, snap.pinned, e.priceChange, settings.PriceChange)
+	m.tracef(symbol, "start=%.8f current=%.8f change=%.4f%% (threshold=%.2f%%) volume=$%d (min=$%d, pinned=%t)",
+		e.startPrice, e.currentPrice, e.priceChange, settings.PriceChange, e.volume, minVolume, snap.pinned)
+
+	if !e.triggered() {
+		log.Debugf("Conditions not met for %s", symbol)
+	}
+	return e
+}
+
+func (m *Monitor) symbolCooldowns() map[string]time.Duration {
+	overrides, err := m.db.GetSymbolCooldowns()
+	if err != nil {
+		log.Errorf("Failed to get cooldown overrides: %v", err)
+		return nil
+	}
+
+	cooldowns := make(map[string]time.Duration, len(overrides))
+	for symbol, seconds := range overrides {
+		cooldowns[symbol] = time.Duration(seconds) * time.Second
+	}
+	return cooldowns
+}
+
+func (m *Monitor) referenceSymbol() string {
+	return strings.ToUpper(m.cfg.Monitoring.RelativeTo)
+}
+
+func (m *Monitor) applyRelative(evaluations []evaluation, settings *database.Settings) {
+	reference := m.referenceSymbol()
+	if reference == "" {
+		return
+	}
+
+	var referenceChange float64
+	found := false
+	for _, e := range evaluations {
+		if e.symbol == reference && e.skip != telegram.SkipNoHistory && e.skip != telegram.SkipPriceTooOld {
+			referenceChange = e.priceChange
+			found = true
+			break
+		}
+	}
+
+	if !found {
+		log.Warnf("No price data for reference symbol %s, using absolute changes", reference)
+		return
+	}
+
+	for i := range evaluations {
+		e := &evaluations[i]
+		if e.symbol == reference {
+			continue
+		}
+		e.priceChange -= referenceChange
+		e.checkChange(settings)
+		e.checkVolume(settings)
+		m.tracef(e.symbol, "relative to %s (%.4f%%): change=%.4f%%", reference, referenceChange, e.priceChange)
+	}
+}
+
+func (m *Monitor) recordAlert(mode, symbol string, priceChange, price float64, volume int, params analysisParams) {
+	direction := "up"
+	if priceChange < 0 {
+		direction = "down"
+	}
+
+	tier := 0
+	if params.settings.PriceChange > 0 {
+		tier = int(math.Abs(priceChange) / params.settings.PriceChange)
+	}
+
+	_, err := m.db.SaveAlert(&database.AlertRecord{
+		Symbol:    symbol,
+		Direction: direction,
+		Change:    priceChange,
+		Tier:      tier,
+		Mode:      mode,
+		Price:     price,
+		Volume:    volume,
+		Interval:  params.settings.TimeInterval,
+		CreatedAt: params.now,
+	})
+	if err != nil {
+		log.Errorf("Failed to save alert for %s: %v", symbol, err)
+	}
+}
+
+func subWindowChanges(history []*PriceData, params analysisParams, currentPrice float64) (float64, float64) {
+	start := priceAt(history, params.cutoffTime)
+	mid := priceAt(history, params.cutoffTime.Add(params.windowEnd.Sub(params.cutoffTime)/2))
+	if start == nil || mid == nil || start.Price <= 0 || mid.Price <= 0 {
+		return 0, 0
+	}
+
+	prior := (mid.Price - start.Price) / start.Price * 100
+	recent := (currentPrice - mid.Price) / mid.Price * 100
+	return prior, recent
+}
+
+func (m *Monitor) trackAcceleration(evaluations []evaluation, params analysisParams) []telegram.SymbolChange {
+	threshold := m.cfg.Monitoring.AccelerationThreshold
+	if threshold <= 0 {
+		return nil
+	}
+
+	var overflow []telegram.SymbolChange
+	accelerating := make(map[string]bool)
+	for _, e := range evaluations {
+		if e.skip != telegram.SkipNone || !e.volumeOK || !e.accelerating(threshold) {
+			continue
+		}
+		accelerating[e.symbol] = true
+
+		if m.accelerating[e.symbol] {
+			continue
+		}
+
+		log.Infof("Price change for %s is accelerating: %.2f%% -> %.2f%%", e.symbol, e.priorChange, e.recentChange)
+		m.tracef(e.symbol, "accelerating: prior=%.4f%% recent=%.4f%%", e.priorChange, e.recentChange)
+		if m.reserveAlerts(1, params.now) == 0 {
+			overflow = append(overflow, telegram.SymbolChange{Symbol: e.symbol, Change: e.recentChange})
+		} else if err := m.bot.SendAccelerationAlert(e.symbol, e.priorChange, e.recentChange, e.volume, params.now); err != nil {
+			log.Errorf("Failed to send acceleration alert for %s: %v", e.symbol, err)
+		}
+		m.recordAlert("acceleration", e.symbol, e.recentChange, e.currentPrice, e.volume, params)
+	}
+
+	m.accelerating = accelerating
+	return overflow
+}
+
+func (m *Monitor) trackSustainedMoves(evaluations []evaluation, params analysisParams) []telegram.SymbolChange {
+	cycles := m.cfg.Monitoring.SustainedCycles
+	if cycles <= 0 {
+		return nil
+	}
+
+	var overflow []telegram.SymbolChange
+
+	seen := make(map[string]bool, len(evaluations))
+	for _, e := range evaluations {
+		seen[e.symbol] = true
+
+		direction := 0
+		if e.changeOK && e.volumeOK && (e.skip == telegram.SkipNone || e.skip == telegram.SkipCooldown) {
+			direction = 1
+			if e.priceChange < 0 {
+				direction = -1
+			}
+		}
+
+		streak := m.sustained[e.symbol]
+		if direction == 0 || direction != streak.direction {
+			if direction == 0 {
+				delete(m.sustained, e.symbol)
+			} else {
+				m.sustained[e.symbol] = sustainedMove{direction: direction, cycles: 1}
+			}
+			continue
+		}
+
+		streak.cycles++
+		m.sustained[e.symbol] = streak
+		m.tracef(e.symbol, "sustained move: %d consecutive cycles", streak.cycles)
+
+		if streak.cycles != cycles {
+			continue
+		}
+
+		log.Infof("Sustained move for %s: %.2f%% for %d consecutive cycles", e.symbol, e.priceChange, streak.cycles)
+		if m.reserveAlerts(1, params.now) == 0 {
+			overflow = append(overflow, telegram.SymbolChange{Symbol: e.symbol, Change: e.priceChange})
+		} else if err := m.bot.SendSustainedAlert(e.symbol, e.priceChange, e.volume, streak.cycles, params.now); err != nil {
+			log.Errorf("Failed to send sustained alert for %s: %v", e.symbol, err)
+		}
+		m.recordAlert("sustained", e.symbol, e.priceChange, e.currentPrice, e.volume, params)
+	}
+
+	for symbol := range m.sustained {
+		if !seen[symbol] {
+			delete(m.sustained, symbol)
+		}
+	}
+	return overflow
+}
+
+func (m *Monitor) Explain(symbol string) telegram.ConditionReport {
+	report := telegram.ConditionReport{Symbol: symbol}
+
+	settings, err := m.settings()
+	if err != nil {
+		log.Errorf("Failed to get settings: %v", err)
+		report.Skip = telegram.SkipNoHistory
+		return report
+	}
+	settings = m.calmSettings(settings, time.Now())
+	report.ChangeThreshold = settings.PriceChange
+	report.MinVolume = settings.MinVolume
+
+	now := time.Now()
+	interval := time.Duration(settings.TimeInterval) * time.Second
+
+	m.mu.RLock()
+	history := m.priceHistory[symbol]
+	if len(history) == 0 {
+		m.mu.RUnlock()
+		report.Skip = telegram.SkipNoHistory
+		return report
+	}
+
+	snapshots := []symbolSnapshot{m.snapshotSymbol(symbol, history)}
+	if reference := m.referenceSymbol(); reference != "" && reference != symbol {
+		if referenceHistory := m.priceHistory[reference]; len(referenceHistory) > 0 {
+			snapshots = append(snapshots, m.snapshotSymbol(reference, referenceHistory))
+		}
+	}
+	m.mu.RUnlock()
+	snap := snapshots[0]
+
+	params := analysisParams{
+		settings:   settings,
+		now:        now,
+		windowEnd:  now,
+		cutoffTime: now.Add(-interval),
+		cooldowns:  m.symbolCooldowns(),
+	}
+	evaluations := make([]evaluation, 0, len(snapshots))
+	for _, s := range snapshots {
+		evaluations = append(evaluations, m.evaluate(s, params))
+	}
+	m.applyRelative(evaluations, settings)
+	e := evaluations[0]
+
+	threshold := time.Duration(m.cfg.Monitoring.StalenessThreshold) * time.Second
+	if threshold > 0 && now.Sub(snap.history[len(snap.history)-1].Timestamp) > threshold && e.skip == telegram.SkipNone {
+		e.skip = telegram.SkipStale
+	}
+
+	report.Skip = e.skip
+	report.CurrentPrice = e.currentPrice
+	report.PriceChange = e.priceChange
+	report.MinVolume = settings.VolumeThreshold(e.priceChange)
+	report.Volume = e.volume
+	report.Pinned = e.pinned
+	report.ChangeOK = e.changeOK
+	report.VolumeOK = e.volumeOK
+	report.Interval = interval
+	report.BaselineAge = e.baselineAge
+	report.BaselineEMA = e.baselineEMA
+	return report
+}
+
+func priceAt(history []*PriceData, t time.Time) *PriceData {
+	for i := len(history) - 1; i >= 0; i-- {
+		if !history[i].Timestamp.After(t) {
+			return history[i]
+		}
+	}
+	return nil
+}
+
+func (m *Monitor) checkStaleness(symbol string, lastUpdate, now time.Time) bool {
+	threshold := time.Duration(m.cfg.Monitoring.StalenessThreshold) * time.Second
+	if threshold <= 0 {
+		return false
+	}
+
+	age := now.Sub(lastUpdate)
+	if age <= threshold {
+		if m.staleSymbols[symbol] {
+			delete(m.staleSymbols, symbol)
+			log.Infof("Price feed for %s recovered", symbol)
+		}
+		return false
+	}
+
+	if !m.staleSymbols[symbol] {
+		m.staleSymbols[symbol] = true
+		log.Warnf("Price feed for %s is stale: last update %s ago", symbol, age.Round(time.Second))
+		go m.bot.NotifyAdmins(fmt.Sprintf("⚠️ Нет новых цен по %s уже %s", html.EscapeString(symbol), age.Round(time.Second)))
+	}
+	return true
+}
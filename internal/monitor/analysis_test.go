@@ -0,0 +1,93 @@
+package monitor
+
+import (
+	"testing"
+	"time"
+
+	"mexc-monitor/internal/database"
+	"mexc-monitor/internal/telegram"
+)
+
+func TestBlacklistedSymbolNotSustainedDuringCooldown(t *testing.T) {
+	var listed []string
+	m, db, _ := newTestMonitor(t, &listed)
+	m.cfg.Monitoring.AlertCooldown = 600
+	m.cfg.Monitoring.SustainedCycles = 3
+
+	if err := db.AddToBlacklist("BTCUSDT", time.Hour); err != nil {
+		t.Fatalf("AddToBlacklist() error = %v", err)
+	}
+
+	now := time.Now()
+	params := analysisParams{
+		settings:   &database.Settings{TimeInterval: 60, PriceChange: 2, MinVolume: 100},
+		now:        now,
+		windowEnd:  now,
+		cutoffTime: now.Add(-time.Minute),
+	}
+	snap := symbolSnapshot{
+		symbol: "BTCUSDT",
+		history: []*PriceData{
+			{Price: 100, Timestamp: now.Add(-time.Minute)},
+			{Price: 110, Timestamp: now},
+		},
+		volume:    VolumeData{Volume: 1000, Timestamp: now},
+		hasVolume: true,
+		lastAlert: now.Add(-time.Minute),
+	}
+
+	e := m.evaluate(snap, params)
+	if e.skip != telegram.SkipBlacklisted {
+		t.Fatalf("evaluate() skip = %v, want SkipBlacklisted", e.skip)
+	}
+
+	m.trackSustainedMoves([]evaluation{e}, params)
+	if streak, ok := m.sustained["BTCUSDT"]; ok {
+		t.Errorf("blacklisted symbol tracked as sustained move: %+v", streak)
+	}
+}
+
+func TestSustainedAlertsShareAlertBudget(t *testing.T) {
+	var listed []string
+	m, _, _ := newTestMonitor(t, &listed)
+	m.cfg.Monitoring.MaxAlertsPerMinute = 1
+	m.cfg.Monitoring.SustainedCycles = 2
+
+	now := time.Now()
+	params := analysisParams{settings: &database.Settings{TimeInterval: 60, PriceChange: 2}, now: now}
+	if got := m.reserveAlerts(1, now); got != 1 {
+		t.Fatalf("reserveAlerts() = %d, want 1", got)
+	}
+
+	e := evaluation{symbol: "BTCUSDT", priceChange: 5, changeOK: true, volumeOK: true}
+	if overflow := m.trackSustainedMoves([]evaluation{e}, params); len(overflow) != 0 {
+		t.Fatalf("first cycle overflow = %v, want none", overflow)
+	}
+	overflow := m.trackSustainedMoves([]evaluation{e}, params)
+	if len(overflow) != 1 || overflow[0].Symbol != "BTCUSDT" || overflow[0].Change != 5 {
+		t.Errorf("sustained alert over budget = %v, want BTCUSDT +5%% in the summary", overflow)
+	}
+}
+
+func TestApplyRelativeRechecksDirectionalVolume(t *testing.T) {
+	var listed []string
+	m, _, _ := newTestMonitor(t, &listed)
+	m.cfg.Monitoring.RelativeTo = "ETHUSDT"
+
+	settings := &database.Settings{PriceChange: 2, MinVolumeUp: 100, MinVolumeDown: 10000}
+	evaluations := []evaluation{
+		{symbol: "BTCUSDT", priceChange: -1, volume: 500},
+		{symbol: "ETHUSDT", priceChange: -5, volume: 500},
+	}
+	evaluations[0].checkChange(settings)
+	evaluations[0].checkVolume(settings)
+	if evaluations[0].volumeOK {
+		t.Fatalf("absolute drop with $500 volume passed the $10000 down threshold")
+	}
+
+	m.applyRelative(evaluations, settings)
+	if e := evaluations[0]; e.priceChange != 4 || !e.changeOK || !e.volumeOK {
+		t.Errorf("relative evaluation = change %.2f%%, changeOK=%t, volumeOK=%t; want +4%% passing both checks",
+			e.priceChange, e.changeOK, e.volumeOK)
+	}
+}
@@ -0,0 +1,105 @@
+package monitor
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"mexc-monitor/internal/database"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const changeFromSession = "session"
+
+type anchorPrice struct {
+	price float64
+	at    time.Time
+	tier  int
+}
+
+func parseSessionOpen(value string) (time.Duration, error) {
+	t, err := time.Parse("15:04", value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid monitoring.session_open %q, expected HH:MM", value)
+	}
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
+
+func (m *Monitor) anchorEnabled() bool {
+	return strings.EqualFold(m.cfg.Monitoring.ChangeReference, changeFromSession)
+}
+
+func (m *Monitor) sessionStart(t time.Time) time.Time {
+	t = t.UTC()
+	start := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC).Add(m.sessionOpen)
+	if start.After(t) {
+		start = start.AddDate(0, 0, -1)
+	}
+	return start
+}
+
+func (m *Monitor) updateAnchor(symbol string, price float64, timestamp time.Time) {
+	if !m.anchorEnabled() {
+		return
+	}
+
+	if start := m.sessionStart(timestamp); start.After(m.anchorSince) {
+		if len(m.anchors) > 0 {
+			log.Infof("New session started at %s UTC, re-anchoring %d symbols", start.Format("2006-01-02 15:04"), len(m.anchors))
+		}
+		m.anchors = make(map[string]anchorPrice)
+		m.anchorSince = start
+	}
+
+	if _, ok := m.anchors[symbol]; !ok {
+		m.anchors[symbol] = anchorPrice{price: price, at: timestamp}
+		m.tracef(symbol, "anchored at %.8f", price)
+	}
+}
+
+func anchorTier(priceChange float64, settings *database.Settings) int {
+	if settings.PriceChange <= 0 {
+		return 0
+	}
+	return int(math.Abs(priceChange) / settings.PriceChange)
+}
+
+func (m *Monitor) raiseAnchorTier(symbol string, priceChange float64, settings *database.Settings) {
+	anchor, ok := m.anchors[symbol]
+	if !ok {
+		return
+	}
+	if tier := anchorTier(priceChange, settings); tier > anchor.tier {
+		anchor.tier = tier
+		m.anchors[symbol] = anchor
+	}
+}
+
+func (m *Monitor) ResetAnchors() (int, bool) {
+	if !m.anchorEnabled() {
+		return 0, false
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	count := len(m.anchors)
+	m.anchors = make(map[string]anchorPrice)
+	m.anchorSince = time.Now().UTC()
+
+	log.Infof("Session anchors reset for %d symbols", count)
+	return count, true
+}
+
+func (m *Monitor) AnchorState() (enabled bool, since time.Time, symbols int) {
+	if !m.anchorEnabled() {
+		return false, time.Time{}, 0
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return true, m.anchorSince, len(m.anchors)
+}
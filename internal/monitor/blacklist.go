@@ -0,0 +1,49 @@
+package monitor
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"mexc-monitor/internal/database"
+
+	log "github.com/sirupsen/logrus"
+)
+
+func parseDefaultBlacklist(entries []string) []database.BlacklistDefault {
+	var defaults []database.BlacklistDefault
+	for _, entry := range entries {
+		symbol, durationStr, timed := strings.Cut(strings.TrimSpace(entry), ":")
+		symbol = strings.ToUpper(strings.TrimSpace(symbol))
+		if symbol == "" {
+			continue
+		}
+
+		var duration time.Duration
+		if timed {
+			seconds, err := strconv.Atoi(strings.TrimSpace(durationStr))
+			if err != nil || seconds <= 0 {
+				log.Warnf("Invalid default_blacklist entry %q, expected SYMBOL or SYMBOL:seconds", entry)
+				continue
+			}
+			duration = time.Duration(seconds) * time.Second
+		}
+
+		defaults = append(defaults, database.BlacklistDefault{Symbol: symbol, Duration: duration})
+	}
+	return defaults
+}
+
+func (m *Monitor) applyDefaultBlacklist() {
+	defaults := parseDefaultBlacklist(m.cfg.Monitoring.DefaultBlacklist)
+
+	applied, err := m.db.ApplyDefaultBlacklist(defaults)
+	if err != nil {
+		log.Errorf("Failed to apply default blacklist: %v", err)
+		return
+	}
+
+	if len(defaults) > 0 {
+		log.Infof("Default blacklist: %d entries configured, %d applied", len(defaults), applied)
+	}
+}
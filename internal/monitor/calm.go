@@ -0,0 +1,62 @@
+package monitor
+
+import (
+	"time"
+
+	"mexc-monitor/internal/database"
+
+	log "github.com/sirupsen/logrus"
+)
+
+func (m *Monitor) Calm(multiplier float64, duration time.Duration) time.Time {
+	m.calmMu.Lock()
+	defer m.calmMu.Unlock()
+
+	if multiplier <= 1 || duration <= 0 {
+		m.calmMultiplier = 0
+		m.calmUntil = time.Time{}
+		log.Info("Calm mode disabled")
+		return time.Time{}
+	}
+
+	m.calmMultiplier = multiplier
+	m.calmUntil = time.Now().Add(duration)
+	log.Infof("Calm mode enabled: thresholds x%.2f until %s", multiplier, m.calmUntil.Format("15:04:05"))
+	return m.calmUntil
+}
+
+func (m *Monitor) CalmState() (float64, time.Time) {
+	m.calmMu.Lock()
+	defer m.calmMu.Unlock()
+
+	m.expireCalm(time.Now())
+	return m.calmMultiplier, m.calmUntil
+}
+
+func (m *Monitor) expireCalm(now time.Time) {
+	if m.calmMultiplier == 0 || now.Before(m.calmUntil) {
+		return
+	}
+
+	m.calmMultiplier = 0
+	m.calmUntil = time.Time{}
+	log.Info("Calm mode expired, thresholds restored")
+	go m.bot.NotifyAdmins("🔔 Временное повышение порогов закончилось, пороги восстановлены")
+}
+
+func (m *Monitor) calmSettings(settings *database.Settings, now time.Time) *database.Settings {
+	m.calmMu.Lock()
+	defer m.calmMu.Unlock()
+
+	m.expireCalm(now)
+	if m.calmMultiplier == 0 {
+		return settings
+	}
+
+	calmed := *settings
+	calmed.PriceChange *= m.calmMultiplier
+	calmed.MinVolume = int(float64(calmed.MinVolume) * m.calmMultiplier)
+	calmed.MinVolumeUp = int(float64(calmed.MinVolumeUp) * m.calmMultiplier)
+	calmed.MinVolumeDown = int(float64(calmed.MinVolumeDown) * m.calmMultiplier)
+	return &calmed
+}
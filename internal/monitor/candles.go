@@ -0,0 +1,138 @@
+package monitor
+
+import (
+	"context"
+	"time"
+
+	"mexc-monitor/internal/mexc"
+	"mexc-monitor/internal/telegram"
+
+	log "github.com/sirupsen/logrus"
+)
+
+var candleIntervals = map[string]time.Duration{
+	"1m":  time.Minute,
+	"5m":  5 * time.Minute,
+	"15m": 15 * time.Minute,
+	"30m": 30 * time.Minute,
+	"60m": time.Hour,
+	"4h":  4 * time.Hour,
+	"1d":  24 * time.Hour,
+}
+
+func (m *Monitor) candlesRoutine(ctx context.Context) {
+	threshold := m.cfg.Monitoring.ConsecutiveCandles
+	if threshold <= 0 || m.client.IsMock() {
+		return
+	}
+
+	interval, ok := candleIntervals[m.cfg.Monitoring.CandleInterval]
+	if !ok {
+		log.Errorf("Unknown candle interval %q, consecutive candle alerts disabled", m.cfg.Monitoring.CandleInterval)
+		return
+	}
+
+	log.Infof("Watching for %d consecutive %s candles", threshold, m.cfg.Monitoring.CandleInterval)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			m.checkCandles(ctx, threshold)
+		}
+	}
+}
+
+func (m *Monitor) checkCandles(ctx context.Context, threshold int) {
+	if m.inMaintenance(time.Now()) {
+		log.Debug("Skipping candle check: maintenance window is active")
+		return
+	}
+
+	m.mu.RLock()
+	symbols := append([]string(nil), m.symbols...)
+	m.mu.RUnlock()
+
+	var overflow []telegram.SymbolChange
+	defer func() { m.sendOverflow(overflow, time.Now()) }()
+
+	for _, symbol := range symbols {
+		if ctx.Err() != nil {
+			return
+		}
+
+		klines, err := m.restClient.GetKlines(symbol, m.cfg.Monitoring.CandleInterval, threshold+2)
+		if err != nil {
+			log.Debugf("Failed to get klines for %s: %v", symbol, err)
+			continue
+		}
+
+		now := time.Now()
+		run, change := consecutiveCandles(closedCandles(klines, now))
+		if run != threshold {
+			continue
+		}
+
+		if blacklisted, err := m.db.IsBlacklisted(symbol); err != nil {
+			log.Errorf("Failed to check blacklist for %s: %v", symbol, err)
+			continue
+		} else if blacklisted {
+			continue
+		}
+
+		log.Infof("%s closed %d consecutive candles in one direction (%.2f%%)", symbol, run, change)
+		if m.reserveAlerts(1, now) == 0 {
+			overflow = append(overflow, telegram.SymbolChange{Symbol: symbol, Change: change})
+		} else if err := m.bot.SendCandlesAlert(symbol, run, change, now); err != nil {
+			log.Errorf("Failed to send candles alert for %s: %v", symbol, err)
+			continue
+		}
+
+		settings, err := m.settings()
+		if err != nil {
+			log.Errorf("Failed to get settings: %v", err)
+			continue
+		}
+		price := klines[len(klines)-1].Close
+		m.recordAlert("candles", symbol, change, price, 0, analysisParams{settings: settings, now: now})
+	}
+}
+
+func closedCandles(klines []mexc.Kline, now time.Time) []mexc.Kline {
+	for len(klines) > 0 && klines[len(klines)-1].CloseTime.After(now) {
+		klines = klines[:len(klines)-1]
+	}
+	return klines
+}
+
+func consecutiveCandles(klines []mexc.Kline) (int, float64) {
+	if len(klines) == 0 {
+		return 0, 0
+	}
+
+	last := klines[len(klines)-1]
+	green := last.Close > last.Open
+	if last.Close == last.Open {
+		return 0, 0
+	}
+
+	run := 0
+	first := last
+	for i := len(klines) - 1; i >= 0; i-- {
+		candle := klines[i]
+		if candle.Close == candle.Open || (candle.Close > candle.Open) != green {
+			break
+		}
+		run++
+		first = candle
+	}
+
+	if first.Open <= 0 {
+		return run, 0
+	}
+	return run, (last.Close - first.Open) / first.Open * 100
+}
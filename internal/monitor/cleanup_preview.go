@@ -0,0 +1,35 @@
+package monitor
+
+import (
+	"time"
+
+	"mexc-monitor/internal/telegram"
+)
+
+func (m *Monitor) CleanupPreview() telegram.CleanupPreview {
+	retention := m.retention()
+	cutoffTime := time.Now().Add(-retention)
+
+	preview := telegram.CleanupPreview{Retention: retention}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	for _, history := range m.priceHistory {
+		preview.PricePoints += len(history)
+		for _, priceData := range history {
+			if !priceData.Timestamp.After(cutoffTime) {
+				preview.ExpiredPricePoints++
+			}
+		}
+	}
+
+	preview.VolumeEntries = len(m.volumeData)
+	for _, volData := range m.volumeData {
+		if volData.Timestamp.Before(cutoffTime) {
+			preview.ExpiredVolumeEntries++
+		}
+	}
+
+	return preview
+}
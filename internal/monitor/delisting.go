@@ -0,0 +1,41 @@
+package monitor
+
+import (
+	log "github.com/sirupsen/logrus"
+)
+
+func (m *Monitor) handleDelisted(symbols []string) {
+	if !m.cfg.Monitoring.DelistNotifications {
+		return
+	}
+
+	for _, symbol := range symbols {
+		watchers, err := m.db.GetWatchers(symbol)
+		if err != nil {
+			log.Errorf("Failed to get watchers of %s: %v", symbol, err)
+			continue
+		}
+
+		if _, err := m.db.RemoveSymbolFromWatchlists(symbol); err != nil {
+			log.Errorf("Failed to remove %s from watchlists: %v", symbol, err)
+		}
+
+		// Permanent entries come from default_blacklist and must survive a
+		// relisting, so only temporary ones are dropped.
+		expiresAt, blacklisted, err := m.db.GetBlacklistExpiry(symbol)
+		if err != nil {
+			log.Errorf("Failed to check blacklist for %s: %v", symbol, err)
+		} else if blacklisted && !expiresAt.IsZero() {
+			if err := m.db.RemoveFromBlacklist(symbol); err != nil {
+				log.Errorf("Failed to remove %s from blacklist: %v", symbol, err)
+			}
+		}
+
+		log.Infof("Symbol %s is no longer listed: notifying %d watchers (blacklisted=%t)", symbol, len(watchers), blacklisted)
+		for _, chatID := range watchers {
+			if err := m.delistNotice(chatID, symbol); err != nil {
+				log.Errorf("Failed to send delisting notice for %s to %d: %v", symbol, chatID, err)
+			}
+		}
+	}
+}
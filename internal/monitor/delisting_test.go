@@ -0,0 +1,114 @@
+package monitor
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"mexc-monitor/internal/config"
+	"mexc-monitor/internal/database"
+	"mexc-monitor/internal/mexc"
+)
+
+type delistNotice struct {
+	chatID int64
+	symbol string
+}
+
+func newTestMonitor(t *testing.T, listed *[]string) (*Monitor, database.Store, chan delistNotice) {
+	t.Helper()
+
+	db, err := database.New(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("failed to open test database: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	cfg := &config.Config{}
+	cfg.MEXC.Mock = true
+	cfg.Monitoring.QuoteAssets = []string{"USDT"}
+	cfg.Monitoring.SessionOpen = "00:00"
+	cfg.Monitoring.DelistNotifications = true
+
+	m, err := New(cfg, db, nil)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	notices := make(chan delistNotice, 10)
+	m.listSymbols = func() ([]mexc.SymbolInfo, error) {
+		infos := make([]mexc.SymbolInfo, 0, len(*listed))
+		for _, symbol := range *listed {
+			base, quote, _ := m.splitter.Split(symbol)
+			infos = append(infos, mexc.SymbolInfo{Symbol: symbol, Status: "ENABLED", BaseAsset: base, QuoteAsset: quote})
+		}
+		return infos, nil
+	}
+	m.delistNotice = func(chatID int64, symbol string) error {
+		notices <- delistNotice{chatID: chatID, symbol: symbol}
+		return nil
+	}
+	return m, db, notices
+}
+
+func TestRefreshSymbolsDetectsDelisting(t *testing.T) {
+	listed := []string{"BTCUSDT", "ETHUSDT", "OLDUSDT"}
+	m, db, notices := newTestMonitor(t, &listed)
+
+	if _, _, err := m.RefreshSymbols(); err != nil {
+		t.Fatalf("RefreshSymbols() error = %v", err)
+	}
+	if _, err := db.AddToWatchlist(42, "OLDUSDT"); err != nil {
+		t.Fatalf("AddToWatchlist() error = %v", err)
+	}
+
+	listed = []string{"BTCUSDT", "ETHUSDT", "NEWUSDT"}
+	added, removed, err := m.RefreshSymbols()
+	if err != nil {
+		t.Fatalf("RefreshSymbols() error = %v", err)
+	}
+	if added != 1 || removed != 1 {
+		t.Errorf("RefreshSymbols() = %d added, %d removed, want 1 and 1", added, removed)
+	}
+
+	select {
+	case notice := <-notices:
+		if notice.chatID != 42 || notice.symbol != "OLDUSDT" {
+			t.Errorf("delisting notice = %+v, want chat 42 about OLDUSDT", notice)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("no delisting notice sent")
+	}
+
+	watchlist, err := db.GetWatchlist(42)
+	if err != nil {
+		t.Fatalf("GetWatchlist() error = %v", err)
+	}
+	if len(watchlist) != 0 {
+		t.Errorf("watchlist after delisting = %v, want empty", watchlist)
+	}
+}
+
+func TestDelistingKeepsPermanentBlacklist(t *testing.T) {
+	var listed []string
+	m, db, _ := newTestMonitor(t, &listed)
+
+	if _, err := db.ApplyDefaultBlacklist([]database.BlacklistDefault{{Symbol: "KEEPUSDT"}}); err != nil {
+		t.Fatalf("ApplyDefaultBlacklist() error = %v", err)
+	}
+	if err := db.AddToBlacklist("TEMPUSDT", time.Hour); err != nil {
+		t.Fatalf("AddToBlacklist() error = %v", err)
+	}
+
+	m.handleDelisted([]string{"KEEPUSDT", "TEMPUSDT"})
+
+	for symbol, want := range map[string]bool{"KEEPUSDT": true, "TEMPUSDT": false} {
+		blacklisted, err := db.IsBlacklisted(symbol)
+		if err != nil {
+			t.Fatalf("IsBlacklisted(%s) error = %v", symbol, err)
+		}
+		if blacklisted != want {
+			t.Errorf("IsBlacklisted(%s) after delisting = %t, want %t", symbol, blacklisted, want)
+		}
+	}
+}
@@ -0,0 +1,61 @@
+package monitor
+
+import (
+	"fmt"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	analysisPeriod       = 5 * time.Second
+	analysisWarnFraction = 0.8
+	analysisTimesKept    = 20
+)
+
+func (m *Monitor) recordAnalysisDuration(took time.Duration) {
+	m.latencyMu.Lock()
+	m.analysisTimes = append(m.analysisTimes, took)
+	if len(m.analysisTimes) > analysisTimesKept {
+		m.analysisTimes = m.analysisTimes[len(m.analysisTimes)-analysisTimesKept:]
+	}
+	wasSlow := m.analysisSlow
+	m.analysisSlow = took >= time.Duration(float64(analysisPeriod)*analysisWarnFraction)
+	slow := m.analysisSlow
+	m.latencyMu.Unlock()
+
+	if !slow {
+		if wasSlow {
+			log.Infof("Analysis cycle back within budget: %s of %s", took.Round(time.Millisecond), analysisPeriod)
+			if m.cfg.Monitoring.NotifySlowAnalysis {
+				go m.bot.NotifyAdmins(fmt.Sprintf("✅ Анализ снова укладывается в интервал: %s из %s",
+					took.Round(time.Millisecond), analysisPeriod))
+			}
+		}
+		return
+	}
+
+	log.Warnf("Analysis cycle took %s of its %s budget, the monitor may fall behind", took.Round(time.Millisecond), analysisPeriod)
+	if !wasSlow && m.cfg.Monitoring.NotifySlowAnalysis {
+		go m.bot.NotifyAdmins(fmt.Sprintf("🐢 Анализ занял %s из %s: монитор не успевает, уменьшите число пар или увеличьте analysis_workers",
+			took.Round(time.Millisecond), analysisPeriod))
+	}
+}
+
+func (m *Monitor) analysisStats() (last, avg, max time.Duration) {
+	m.latencyMu.Lock()
+	defer m.latencyMu.Unlock()
+
+	if len(m.analysisTimes) == 0 {
+		return 0, 0, 0
+	}
+
+	var total time.Duration
+	for _, took := range m.analysisTimes {
+		total += took
+		if took > max {
+			max = took
+		}
+	}
+	return m.analysisTimes[len(m.analysisTimes)-1], total / time.Duration(len(m.analysisTimes)), max
+}
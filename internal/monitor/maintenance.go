@@ -0,0 +1,18 @@
+package monitor
+
+import (
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// inMaintenance reports whether an active MEXC maintenance window suppresses
+// alerts. A failed lookup does not block alerts.
+func (m *Monitor) inMaintenance(now time.Time) bool {
+	inMaintenance, err := m.db.IsInMaintenance(now)
+	if err != nil {
+		log.Errorf("Failed to check maintenance windows: %v", err)
+		return false
+	}
+	return inMaintenance
+}
@@ -3,7 +3,10 @@ package monitor
 import (
 	"context"
 	"fmt"
+	"math"
+	"sort"
 	"strconv"
+	"strings"
 	"sync"
 	"time"
 
@@ -15,15 +18,52 @@ import (
 	log "github.com/sirupsen/logrus"
 )
 
+const defaultRetention = 10 * time.Minute
+
 type Monitor struct {
-	cfg          *config.Config
-	db           *database.Database
-	bot          *telegram.Bot
-	client       *mexc.Client
-	mu           sync.RWMutex
-	priceHistory map[string][]*PriceData
-	volumeData   map[string]*VolumeData
-	stopChan     chan struct{}
+	cfg              *config.Config
+	db               database.Store
+	bot              *telegram.Bot
+	client           *mexc.Client
+	restClient       *mexc.RESTClient
+	mu               sync.RWMutex
+	priceHistory     map[string][]*PriceData
+	volumeData       map[string]*VolumeData
+	ema              map[string]float64
+	tickerVolumes    map[string]float64
+	tickSizes        map[string]float64
+	anchors          map[string]anchorPrice
+	anchorSince      time.Time
+	sessionOpen      time.Duration
+	splitter         *mexc.SymbolSplitter
+	symbols          []string
+	alwaysSymbols    map[string]bool
+	staleSymbols     map[string]bool
+	lastWindowEnd    time.Time
+	lastDataAt       time.Time
+	wsActive         bool
+	wsSymbols        map[string]bool
+	lastAlerts       map[string]time.Time
+	sustained        map[string]sustainedMove
+	analysisMu       sync.Mutex
+	latencyMu        sync.Mutex
+	analysisTimes    []time.Duration
+	analysisSlow     bool
+	budgetMu         sync.Mutex
+	sentAlerts       []time.Time
+	accelerating     map[string]bool
+	settingsMu       sync.Mutex
+	lastSettings     *database.Settings
+	settingsDegraded bool
+	calmMu           sync.Mutex
+	calmMultiplier   float64
+	calmUntil        time.Time
+	traceMu          sync.Mutex
+	tracedSymbols    map[string]time.Time
+	routines         sync.WaitGroup
+	stopChan         chan struct{}
+	listSymbols      func() ([]mexc.SymbolInfo, error)
+	delistNotice     func(chatID int64, symbol string) error
 }
 
 type PriceData struct {
@@ -36,48 +76,136 @@ type VolumeData struct {
 	Timestamp time.Time
 }
 
-func New(cfg *config.Config, db *database.Database, bot *telegram.Bot) (*Monitor, error) {
-	client := mexc.NewClient(cfg.MEXC.WebSocketURL)
+func New(cfg *config.Config, db database.Store, bot *telegram.Bot) (*Monitor, error) {
+	tlsConfig, err := mexc.LoadTLSConfig(cfg.MEXC.TLS.CAFile, cfg.MEXC.TLS.CertFile, cfg.MEXC.TLS.KeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load TLS config: %w", err)
+	}
+
+	client := mexc.NewClient(append([]string{cfg.MEXC.WebSocketURL}, cfg.MEXC.WebSocketURLs...), tlsConfig)
+	if cfg.MEXC.Mock {
+		log.Warn("MEXC mock mode enabled: using synthetic price data")
+		client = mexc.NewMockClient()
+	}
+
+	alwaysSymbols := make(map[string]bool)
+	for _, symbol := range cfg.Monitoring.AlwaysSymbols {
+		alwaysSymbols[strings.ToUpper(symbol)] = true
+	}
+
+	sessionOpen, err := parseSessionOpen(cfg.Monitoring.SessionOpen)
+	if err != nil {
+		return nil, err
+	}
 
-	return &Monitor{
-		cfg:          cfg,
-		db:           db,
-		bot:          bot,
-		client:       client,
-		priceHistory: make(map[string][]*PriceData),
-		volumeData:   make(map[string]*VolumeData),
-		stopChan:     make(chan struct{}),
-	}, nil
+	m := &Monitor{
+		cfg:           cfg,
+		db:            db,
+		bot:           bot,
+		client:        client,
+		restClient:    mexc.NewRESTClient(tlsConfig, cfg.MEXC.UserAgent, cfg.MEXC.Headers),
+		priceHistory:  make(map[string][]*PriceData),
+		volumeData:    make(map[string]*VolumeData),
+		ema:           make(map[string]float64),
+		tickerVolumes: make(map[string]float64),
+		tickSizes:     make(map[string]float64),
+		anchors:       make(map[string]anchorPrice),
+		sessionOpen:   sessionOpen,
+		splitter:      mexc.NewSymbolSplitter(nil),
+		alwaysSymbols: alwaysSymbols,
+		staleSymbols:  make(map[string]bool),
+		lastAlerts:    make(map[string]time.Time),
+		sustained:     make(map[string]sustainedMove),
+		tracedSymbols: make(map[string]time.Time),
+		stopChan:      make(chan struct{}),
+	}
+	m.listSymbols = m.exchangeSymbols
+	m.delistNotice = bot.SendDelistNotice
+	return m, nil
 }
 
 func (m *Monitor) Start(ctx context.Context) error {
 	log.Info("Starting MEXC monitor...")
 
-	symbols, err := m.client.GetSpotSymbols()
+	symbols, err := m.fetchSymbols()
 	if err != nil {
 		return fmt.Errorf("failed to get symbols: %w", err)
 	}
 
-	log.Infof("Monitoring %d symbols", len(symbols))
+	m.mu.Lock()
+	m.symbols = symbols
+	m.lastDataAt = time.Now()
+	m.mu.Unlock()
 
-	go m.restPollingRoutine(ctx, symbols)
+	m.loadTickSizes()
+	m.loadSymbolAssets()
+	m.applyDefaultBlacklist()
 
-	go m.cleanupRoutine(ctx)
+	if lastAlerts, err := m.db.GetLastAlerts(); err != nil {
+		log.Errorf("Failed to load alert cooldowns: %v", err)
+	} else {
+		m.mu.Lock()
+		m.lastAlerts = lastAlerts
+		m.mu.Unlock()
+		log.Infof("Loaded alert cooldowns for %d symbols", len(lastAlerts))
+	}
 
-	go m.analysisRoutine(ctx)
+	log.Infof("Monitoring %d symbols (%d always monitored)", len(symbols), len(m.alwaysSymbols))
+
+	if m.client.IsMock() {
+		m.client.OnTrade(m.handleTrade)
+		m.client.OnTicker(m.handleTicker)
+		if err := m.client.Connect(); err != nil {
+			return fmt.Errorf("failed to start mock client: %w", err)
+		}
+		defer m.client.Disconnect()
+	} else {
+		if wsSymbols, _ := m.splitBySource(symbols); len(wsSymbols) > 0 {
+			if err := m.startWebSocket(wsSymbols); err != nil {
+				log.Errorf("Failed to start WebSocket, polling all symbols over REST: %v", err)
+			} else {
+				defer m.client.Disconnect()
+			}
+		}
+		m.goRoutine(ctx, m.restPollingRoutine)
+	}
+
+	m.goRoutine(ctx, m.cleanupRoutine)
+
+	m.goRoutine(ctx, m.analysisRoutine)
+
+	m.goRoutine(ctx, m.watchdogRoutine)
+
+	m.goRoutine(ctx, m.candlesRoutine)
+
+	m.notifyStartup(symbols)
 
 	<-ctx.Done()
 
 	log.Info("Stopping MEXC monitor...")
+	m.routines.Wait()
+	log.Info("MEXC monitor stopped")
 	return nil
 }
 
+func (m *Monitor) goRoutine(ctx context.Context, routine func(ctx context.Context)) {
+	m.routines.Add(1)
+	go func() {
+		defer m.routines.Done()
+		routine(ctx)
+	}()
+}
+
 func (m *Monitor) handleTrade(data interface{}) {
 	trade, ok := data.(mexc.TradeData)
 	if !ok {
 		return
 	}
 
+	if m.volumeFromTicker() {
+		return
+	}
+
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
@@ -95,15 +223,19 @@ func (m *Monitor) handleTrade(data interface{}) {
 
 	volumeUSD := int(price * quantity)
 
-	if volData, exists := m.volumeData[trade.Symbol]; exists {
-		volData.Volume += volumeUSD
-		volData.Timestamp = time.Now()
-	} else {
-		m.volumeData[trade.Symbol] = &VolumeData{
-			Volume:    volumeUSD,
-			Timestamp: time.Now(),
-		}
+	m.tracef(trade.Symbol, "trade price=%s qty=%s volume=$%d buyer_maker=%t",
+		trade.Price, trade.Quantity, volumeUSD, trade.IsBuyer)
+
+	if !m.countsTowardVolume(price * quantity) {
+		m.tracef(trade.Symbol, "trade below min_trade_size, ignored")
+		return
 	}
+
+	m.addVolume(trade.Symbol, volumeUSD)
+}
+
+func (m *Monitor) countsTowardVolume(tradeUSD float64) bool {
+	return tradeUSD >= m.cfg.Monitoring.MinTradeSize
 }
 
 func (m *Monitor) handleTicker(data interface{}) {
@@ -121,20 +253,154 @@ func (m *Monitor) handleTicker(data interface{}) {
 		return
 	}
 
-	priceData := &PriceData{
+	m.tracef(ticker.Symbol, "ticker price=%s", ticker.Price)
+
+	m.appendPrice(ticker.Symbol, price, time.Now())
+
+	if m.volumeFromTicker() {
+		m.applyTickerVolume(ticker.Symbol, ticker.QuoteVolume)
+	}
+}
+
+func (m *Monitor) appendPrice(symbol string, price float64, timestamp time.Time) {
+	price = m.roundToTick(symbol, price)
+	history := m.priceHistory[symbol]
+	m.lastDataAt = timestamp
+
+	if gap := time.Duration(m.cfg.Monitoring.GapThreshold) * time.Second; gap > 0 && len(history) > 0 {
+		if last := history[len(history)-1]; timestamp.Sub(last.Timestamp) > gap {
+			log.Debugf("Price gap of %s for %s, resetting baseline", timestamp.Sub(last.Timestamp).Round(time.Second), symbol)
+			m.tracef(symbol, "gap of %s since last price, history reset", timestamp.Sub(last.Timestamp).Round(time.Second))
+			history = nil
+			delete(m.ema, symbol)
+		}
+	}
+
+	if alpha := m.cfg.Monitoring.EMAAlpha; alpha > 0 {
+		if ema, ok := m.ema[symbol]; ok {
+			m.ema[symbol] = alpha*price + (1-alpha)*ema
+		} else {
+			m.ema[symbol] = price
+		}
+	}
+
+	m.updateAnchor(symbol, price, timestamp)
+
+	if m.cfg.Monitoring.DedupePrices && len(history) >= 2 {
+		last := history[len(history)-1]
+		prev := history[len(history)-2]
+		if m.samePrice(last.Price, price) && m.samePrice(prev.Price, price) {
+			last.Timestamp = timestamp
+			return
+		}
+	}
+
+	m.priceHistory[symbol] = append(history, &PriceData{
 		Price:     price,
-		Timestamp: time.Now(),
+		Timestamp: timestamp,
+	})
+}
+
+func (m *Monitor) loadTickSizes() {
+	if !m.cfg.Monitoring.RoundToTick || m.client.IsMock() {
+		return
 	}
 
-	if history, exists := m.priceHistory[ticker.Symbol]; exists {
-		m.priceHistory[ticker.Symbol] = append(history, priceData)
-	} else {
-		m.priceHistory[ticker.Symbol] = []*PriceData{priceData}
+	tickSizes, err := m.restClient.GetTickSizes()
+	if err != nil {
+		log.Warnf("Failed to load tick sizes, prices will not be rounded: %v", err)
+		return
+	}
+
+	m.mu.Lock()
+	m.tickSizes = tickSizes
+	m.mu.Unlock()
+
+	log.Infof("Loaded tick sizes for %d symbols", len(tickSizes))
+}
+
+func (m *Monitor) loadSymbolAssets() {
+	if m.cfg.Display.Separator == "" || m.client.IsMock() {
+		return
+	}
+
+	exchangeInfo, err := m.restClient.GetExchangeInfo()
+	if err != nil {
+		log.Warnf("Failed to load base/quote assets, splitting symbols by known quotes: %v", err)
+		return
+	}
+
+	m.mu.Lock()
+	m.splitter = mexc.NewSymbolSplitter(exchangeInfo.Symbols)
+	m.mu.Unlock()
+
+	log.Infof("Loaded base/quote assets for %d symbols", len(exchangeInfo.Symbols))
+}
+
+func (m *Monitor) QuoteVariants(base string) []string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var variants []string
+	for _, symbol := range m.symbols {
+		if symbolBase, _, ok := m.splitter.Split(symbol); ok && symbolBase == base {
+			variants = append(variants, symbol)
+		}
 	}
+	sort.Strings(variants)
+	return variants
+}
+
+func (m *Monitor) TradeURL(symbol string) string {
+	m.mu.RLock()
+	splitter := m.splitter
+	m.mu.RUnlock()
+
+	base, quote, ok := splitter.Split(symbol)
+	if !ok {
+		return ""
+	}
+	return mexc.TradeURL(base, quote)
+}
+
+func (m *Monitor) FormatSymbol(symbol string) string {
+	separator := m.cfg.Display.Separator
+	if separator == "" {
+		return symbol
+	}
+
+	m.mu.RLock()
+	splitter := m.splitter
+	m.mu.RUnlock()
+
+	base, quote, ok := splitter.Split(symbol)
+	if !ok {
+		return symbol
+	}
+	return base + separator + quote
+}
+
+func (m *Monitor) roundToTick(symbol string, price float64) float64 {
+	tick, ok := m.tickSizes[symbol]
+	if !ok {
+		return price
+	}
+
+	decimals := int(math.Ceil(-math.Log10(tick)))
+	if decimals < 0 {
+		decimals = 0
+	}
+	scale := math.Pow10(decimals)
+	ticks := math.Round(price / tick)
+	return math.Round(ticks*tick*scale) / scale
+}
+
+func (m *Monitor) samePrice(a, b float64) bool {
+	return math.Abs(a-b) <= m.cfg.Monitoring.PriceEpsilon
 }
 
 func (m *Monitor) analysisRoutine(ctx context.Context) {
-	ticker := time.NewTicker(5 * time.Second)
+	ticker := time.NewTicker(analysisPeriod)
 	defer ticker.Stop()
 
 	for {
@@ -142,108 +408,244 @@ func (m *Monitor) analysisRoutine(ctx context.Context) {
 		case <-ctx.Done():
 			return
 		case <-ticker.C:
-			m.analyzeData()
+			m.runAnalysis()
+			m.checkPriceLevels()
 		}
 	}
 }
 
-func (m *Monitor) analyzeData() {
-	log.Debug("Starting data analysis...")
+func (m *Monitor) checkPriceLevels() {
+	// Levels stay armed during maintenance and fire once the window ends.
+	if m.inMaintenance(time.Now()) {
+		return
+	}
 
-	settings, err := m.db.GetSettings()
+	alerts, err := m.db.GetAllPriceAlerts()
 	if err != nil {
-		log.Errorf("Failed to get settings: %v", err)
+		log.Errorf("Failed to get price alerts: %v", err)
+		return
+	}
+	if len(alerts) == 0 {
 		return
 	}
 
-	now := time.Now()
-	cutoffTime := now.Add(-time.Duration(settings.TimeInterval) * time.Second)
-
-	log.Debugf("Analysis settings: time_interval=%d, price_change=%.2f%%, min_volume=%d",
-		settings.TimeInterval, settings.PriceChange, settings.MinVolume)
-
-	m.mu.Lock()
-	defer m.mu.Unlock()
-
-	log.Debugf("Analyzing %d symbols", len(m.priceHistory))
+	m.mu.RLock()
+	prices := make(map[string]float64, len(alerts))
+	for _, alert := range alerts {
+		if history := m.priceHistory[alert.Symbol]; len(history) > 0 {
+			prices[alert.Symbol] = history[len(history)-1].Price
+		}
+	}
+	m.mu.RUnlock()
 
-	for symbol, history := range m.priceHistory {
-		if len(history) == 0 {
-			log.Debugf("Skipping %s: no price history", symbol)
+	for _, alert := range alerts {
+		price, ok := prices[alert.Symbol]
+		if !ok {
 			continue
 		}
 
-		currentPrice := history[len(history)-1].Price
-		currentTime := history[len(history)-1].Timestamp
-
-		log.Debugf("Analyzing %s: current price=%.6f, time=%s",
-			symbol, currentPrice, currentTime.Format("15:04:05"))
+		crossed := price >= alert.Level
+		if !alert.Above {
+			crossed = price <= alert.Level
+		}
 
-		if currentTime.Before(cutoffTime) {
-			log.Debugf("Skipping %s: price too old", symbol)
+		if !crossed {
+			if alert.Repeat && !alert.Armed {
+				if err := m.db.SetPriceAlertArmed(alert.ID, true); err != nil {
+					log.Errorf("Failed to re-arm price alert %d: %v", alert.ID, err)
+				}
+			}
 			continue
 		}
 
-		if blacklisted, err := m.db.IsBlacklisted(symbol); err != nil {
-			log.Errorf("Failed to check blacklist for %s: %v", symbol, err)
-			continue
-		} else if blacklisted {
+		if !alert.Armed {
 			continue
 		}
 
-		volData, exists := m.volumeData[symbol]
-		if !exists || volData.Timestamp.Before(cutoffTime) {
+		log.Infof("Price level crossed for %s: price=%.6f level=%.6f (alert %d)",
+			alert.Symbol, price, alert.Level, alert.ID)
+		if err := m.bot.SendLevelAlert(alert.ChatID, alert.Symbol, alert.Above, alert.Level, price); err != nil {
+			log.Errorf("Failed to send price level alert %d: %v", alert.ID, err)
 			continue
 		}
 
-		var startPrice float64
-		found := false
-		targetTime := now.Add(-time.Duration(settings.TimeInterval) * time.Second)
+		if alert.Repeat {
+			err = m.db.SetPriceAlertArmed(alert.ID, false)
+		} else {
+			err = m.db.DeletePriceAlert(alert.ID)
+		}
+		if err != nil {
+			log.Errorf("Failed to update price alert %d: %v", alert.ID, err)
+		}
+	}
+}
 
-		for i := len(history) - 1; i >= 0; i-- {
-			if history[i].Timestamp.Before(targetTime) ||
-				history[i].Timestamp.Equal(targetTime) {
-				startPrice = history[i].Price
-				found = true
-				break
-			}
+func (m *Monitor) withAlwaysSymbols(symbols []string) []string {
+	monitored := make(map[string]bool, len(symbols))
+	for _, symbol := range symbols {
+		monitored[symbol] = true
+	}
+
+	for symbol := range m.alwaysSymbols {
+		if !monitored[symbol] {
+			symbols = append(symbols, symbol)
+		}
+	}
+	return symbols
+}
+
+func dedupeSymbols(symbols []string) []string {
+	seen := make(map[string]bool, len(symbols))
+	unique := make([]string, 0, len(symbols))
+	var duplicates []string
+
+	for _, symbol := range symbols {
+		if seen[symbol] {
+			duplicates = append(duplicates, symbol)
+			continue
 		}
+		seen[symbol] = true
+		unique = append(unique, symbol)
+	}
+
+	if len(duplicates) > 0 {
+		log.Warnf("Ignoring %d duplicate symbols: %s", len(duplicates), strings.Join(duplicates, ", "))
+	}
+	return unique
+}
+
+func (m *Monitor) exchangeSymbols() ([]mexc.SymbolInfo, error) {
+	if !m.client.IsMock() {
+		return m.restClient.GetActiveSymbols()
+	}
+
+	symbols, err := m.client.GetSpotSymbols()
+	if err != nil {
+		return nil, err
+	}
+	infos := make([]mexc.SymbolInfo, 0, len(symbols))
+	for _, symbol := range symbols {
+		base, quote, _ := m.splitter.Split(symbol)
+		infos = append(infos, mexc.SymbolInfo{Symbol: symbol, Status: "ENABLED", BaseAsset: base, QuoteAsset: quote})
+	}
+	return infos, nil
+}
 
-		if !found && len(history) > 0 {
-			startPrice = history[0].Price
+func (m *Monitor) quoteFiltered(infos []mexc.SymbolInfo) []string {
+	quotes := make(map[string]bool, len(m.cfg.Monitoring.QuoteAssets))
+	for _, quote := range m.cfg.Monitoring.QuoteAssets {
+		quotes[strings.ToUpper(quote)] = true
+	}
+
+	symbols := make([]string, 0, len(infos))
+	for _, info := range infos {
+		if len(quotes) == 0 || quotes[strings.ToUpper(info.QuoteAsset)] {
+			symbols = append(symbols, info.Symbol)
 		}
+	}
+	return symbols
+}
 
-		log.Debugf("Price analysis for %s: start=%.6f, current=%.6f",
-			symbol, startPrice, currentPrice)
+func (m *Monitor) fetchSymbols() ([]string, error) {
+	infos, err := m.listSymbols()
+	if err != nil {
+		return nil, err
+	}
+	var symbols []string
+	if len(m.cfg.Monitoring.IncludeOnly) > 0 {
+		symbols = m.includeOnly(infos)
+	} else {
+		symbols = m.quoteFiltered(infos)
+	}
+	symbols = m.withAlwaysSymbols(symbols)
+	if reference := m.referenceSymbol(); reference != "" {
+		symbols = append(symbols, reference)
+	}
+	return dedupeSymbols(symbols), nil
+}
+
+func (m *Monitor) includeOnly(infos []mexc.SymbolInfo) []string {
+	active := make(map[string]bool, len(infos))
+	for _, info := range infos {
+		active[info.Symbol] = true
+	}
 
-		priceChange := 0.0
-		if startPrice > 0 {
-			priceChange = ((currentPrice - startPrice) / startPrice) * 100
+	var included []string
+	for _, symbol := range m.cfg.Monitoring.IncludeOnly {
+		symbol = strings.ToUpper(symbol)
+		if !active[symbol] {
+			log.Warnf("include_only symbol %s is not an active spot symbol, skipping", symbol)
+			continue
 		}
+		included = append(included, symbol)
+	}
 
-		log.Debugf("Price change for %s: %.4f%%", symbol, priceChange)
+	log.Infof("include_only: monitoring %d of %d active symbols", len(included), len(infos))
+	return included
+}
 
-		log.Debugf("Checking conditions for %s: volume=%d (min=%d), price_change=%.4f%% (threshold=%.2f%%)",
-			symbol, volData.Volume, settings.MinVolume, priceChange, settings.PriceChange)
+func (m *Monitor) currentSymbols() []string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return append([]string(nil), m.symbols...)
+}
 
-		if volData.Volume >= settings.MinVolume &&
-			(priceChange >= settings.PriceChange || priceChange <= -settings.PriceChange) {
-			log.Infof("Conditions met for %s! Sending alert...", symbol)
-			if err := m.bot.SendAlert(symbol, priceChange, volData.Volume, now); err != nil {
-				log.Errorf("Failed to send alert for %s: %v", symbol, err)
-			} else {
-				log.Infof("Alert sent for %s: %.2f%% change, $%d volume",
-					symbol, priceChange, volData.Volume)
-			}
+func (m *Monitor) Symbols() []string {
+	return m.currentSymbols()
+}
+
+func (m *Monitor) RefreshSymbols() (int, int, error) {
+	symbols, err := m.fetchSymbols()
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to get symbols: %w", err)
+	}
+
+	fresh := make(map[string]bool, len(symbols))
+	for _, symbol := range symbols {
+		fresh[symbol] = true
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
 
+	old := make(map[string]bool, len(m.symbols))
+	for _, symbol := range m.symbols {
+		old[symbol] = true
+	}
+
+	added := 0
+	for _, symbol := range symbols {
+		if !old[symbol] {
+			added++
+		}
+	}
+	var delisted []string
+	for symbol := range old {
+		if !fresh[symbol] {
+			delisted = append(delisted, symbol)
+			delete(m.priceHistory, symbol)
 			delete(m.volumeData, symbol)
-		} else {
-			log.Debugf("Conditions not met for %s", symbol)
+			delete(m.ema, symbol)
+			delete(m.tickerVolumes, symbol)
+			delete(m.staleSymbols, symbol)
 		}
 	}
+
+	m.symbols = symbols
+
+	go m.loadTickSizes()
+	if m.wsActive && (added > 0 || len(delisted) > 0) {
+		go m.updateStreams(symbols)
+	}
+	if len(delisted) > 0 {
+		go m.handleDelisted(delisted)
+	}
+
+	log.Infof("Symbol list refreshed: %d symbols (%d added, %d removed)", len(symbols), added, len(delisted))
+	return added, len(delisted), nil
 }
 
-func (m *Monitor) restPollingRoutine(ctx context.Context, symbols []string) {
+func (m *Monitor) restPollingRoutine(ctx context.Context) {
 	ticker := time.NewTicker(5 * time.Second)
 	defer ticker.Stop()
 
@@ -254,15 +656,13 @@ func (m *Monitor) restPollingRoutine(ctx context.Context, symbols []string) {
 		case <-ctx.Done():
 			return
 		case <-ticker.C:
-			m.pollPrices(symbols)
+			m.pollPrices(m.restSymbols())
 		}
 	}
 }
 
 func (m *Monitor) pollPrices(symbols []string) {
-	restClient := mexc.NewRESTClient()
-
-	tickers, err := restClient.GetAllTickers()
+	tickers, err := m.restClient.GetAllTickers()
 	if err != nil {
 		log.Errorf("Failed to get tickers: %v", err)
 		return
@@ -287,24 +687,16 @@ func (m *Monitor) pollPrices(symbols []string) {
 			continue
 		}
 
-		priceData := &PriceData{
-			Price:     price,
-			Timestamp: time.Now(),
-		}
-
 		m.mu.Lock()
-		if history, exists := m.priceHistory[ticker.Symbol]; exists {
-			m.priceHistory[ticker.Symbol] = append(history, priceData)
-		} else {
-			m.priceHistory[ticker.Symbol] = []*PriceData{priceData}
-		}
+		m.appendPrice(ticker.Symbol, price, time.Now())
 		m.mu.Unlock()
 
 		log.Debugf("Updated price for %s: %f", ticker.Symbol, price)
+		m.tracef(ticker.Symbol, "polled price=%s", ticker.Price)
 	}
 
 	for _, symbol := range symbols {
-		trades, err := restClient.GetRecentTrades(symbol)
+		trades, err := m.restClient.GetRecentTrades(symbol)
 		if err != nil {
 			log.Debugf("Failed to get trades for %s: %v", symbol, err)
 			continue
@@ -320,6 +712,9 @@ func (m *Monitor) pollPrices(symbols []string) {
 			if err != nil {
 				continue
 			}
+			if !m.countsTowardVolume(price * qty) {
+				continue
+			}
 			totalVolume += int(price * qty)
 		}
 
@@ -331,9 +726,155 @@ func (m *Monitor) pollPrices(symbols []string) {
 		m.mu.Unlock()
 
 		log.Debugf("Updated volume for %s: $%d", symbol, totalVolume)
+		m.tracef(symbol, "polled %d trades, volume=$%d", len(trades), totalVolume)
+	}
+}
+
+func (m *Monitor) PriceSeries(symbol string) []float64 {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	history := m.priceHistory[symbol]
+	prices := make([]float64, len(history))
+	for i, priceData := range history {
+		prices[i] = priceData.Price
+	}
+	return prices
+}
+
+func (m *Monitor) CurrentPrice(symbol string) (float64, error) {
+	if m.client.IsMock() {
+		m.mu.RLock()
+		defer m.mu.RUnlock()
+
+		history := m.priceHistory[symbol]
+		if len(history) == 0 {
+			return 0, fmt.Errorf("no price data for %s", symbol)
+		}
+		return history[len(history)-1].Price, nil
+	}
+
+	ticker, err := m.restClient.GetTicker(symbol)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.ParseFloat(ticker.Price, 64)
+}
+
+func (m *Monitor) TopVolumes(limit int) []telegram.SymbolVolume {
+	m.mu.RLock()
+	volumes := make([]telegram.SymbolVolume, 0, len(m.volumeData))
+	for symbol, volData := range m.volumeData {
+		volumes = append(volumes, telegram.SymbolVolume{
+			Symbol: symbol,
+			Volume: volData.Volume,
+		})
+	}
+	m.mu.RUnlock()
+
+	sort.Slice(volumes, func(i, j int) bool {
+		return volumes[i].Volume > volumes[j].Volume
+	})
+
+	if len(volumes) > limit {
+		volumes = volumes[:limit]
+	}
+	return volumes
+}
+
+func (m *Monitor) TopMovers(limit int, gainers bool) []telegram.SymbolChange {
+	settings, err := m.settings()
+	if err != nil {
+		log.Errorf("Failed to get settings: %v", err)
+		return nil
+	}
+
+	now := time.Now()
+	cutoffTime := now.Add(-time.Duration(settings.TimeInterval) * time.Second)
+
+	m.mu.RLock()
+	var movers []telegram.SymbolChange
+	for symbol, history := range m.priceHistory {
+		if len(history) == 0 {
+			continue
+		}
+
+		startPrice := history[0].Price
+		if start := priceAt(history, cutoffTime); start != nil {
+			startPrice = start.Price
+		}
+		if startPrice <= 0 {
+			continue
+		}
+
+		change := ((history[len(history)-1].Price - startPrice) / startPrice) * 100
+		if (gainers && change > 0) || (!gainers && change < 0) {
+			movers = append(movers, telegram.SymbolChange{Symbol: symbol, Change: change})
+		}
+	}
+	m.mu.RUnlock()
+
+	sort.Slice(movers, func(i, j int) bool {
+		return math.Abs(movers[i].Change) > math.Abs(movers[j].Change)
+	})
+
+	if len(movers) > limit {
+		movers = movers[:limit]
+	}
+	return movers
+}
+
+func (m *Monitor) Diagnostics() telegram.Diagnostics {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	points := 0
+	for _, history := range m.priceHistory {
+		points += len(history)
+	}
+
+	endpoint := ""
+	if m.wsActive || m.client.IsMock() {
+		endpoint = m.client.ActiveURL()
+	}
+
+	last, avg, max := m.analysisStats()
+
+	return telegram.Diagnostics{
+		Symbols:        len(m.symbols),
+		HistoryPoints:  points,
+		VolumeSymbols:  len(m.volumeData),
+		Endpoint:       endpoint,
+		AnalysisLast:   last,
+		AnalysisAvg:    avg,
+		AnalysisMax:    max,
+		AnalysisBudget: analysisPeriod,
 	}
 }
 
+func (m *Monitor) Reset() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	symbols := make(map[string]bool, len(m.priceHistory))
+	for symbol := range m.priceHistory {
+		symbols[symbol] = true
+	}
+	for symbol := range m.volumeData {
+		symbols[symbol] = true
+	}
+
+	m.priceHistory = make(map[string][]*PriceData)
+	m.volumeData = make(map[string]*VolumeData)
+	m.ema = make(map[string]float64)
+	m.tickerVolumes = make(map[string]float64)
+	m.staleSymbols = make(map[string]bool)
+	m.anchors = make(map[string]anchorPrice)
+
+	log.Infof("Price history and volume data reset for %d symbols", len(symbols))
+	return len(symbols)
+}
+
 func (m *Monitor) cleanupRoutine(ctx context.Context) {
 	ticker := time.NewTicker(5 * time.Minute)
 	defer ticker.Stop()
@@ -348,13 +889,48 @@ func (m *Monitor) cleanupRoutine(ctx context.Context) {
 	}
 }
 
+func (m *Monitor) retention() time.Duration {
+	settings, err := m.settings()
+	if err != nil {
+		log.Errorf("Failed to get settings, using default retention: %v", err)
+		return defaultRetention
+	}
+	if settings.Retention > 0 {
+		return time.Duration(settings.Retention) * time.Minute
+	}
+	return defaultRetention
+}
+
 func (m *Monitor) cleanup() {
 	if err := m.db.CleanupExpiredBlacklist(); err != nil {
 		log.Errorf("Failed to cleanup blacklist: %v", err)
 	}
 
+	if err := m.db.CleanupExpiredMutes(); err != nil {
+		log.Errorf("Failed to cleanup user mutes: %v", err)
+	}
+
+	if err := m.db.CleanupExpiredAlertKeys(); err != nil {
+		log.Errorf("Failed to cleanup sent alert keys: %v", err)
+	}
+
+	if err := m.db.CleanupExpiredMaintenance(); err != nil {
+		log.Errorf("Failed to cleanup maintenance windows: %v", err)
+	}
+
+	if days := m.cfg.Database.AlertRetentionDays; days > 0 {
+		if pruned, err := m.db.PruneAlerts(time.Now().AddDate(0, 0, -days)); err != nil {
+			log.Errorf("Failed to prune alert history: %v", err)
+		} else if pruned > 0 {
+			log.Infof("Pruned %d alerts older than %d days", pruned, days)
+		}
+	}
+
+	retention := m.retention()
 	now := time.Now()
-	cutoffTime := now.Add(-10 * time.Minute)
+	cutoffTime := now.Add(-retention)
+
+	log.Debugf("Cleaning up data older than %s", retention)
 
 	m.mu.Lock()
 	defer m.mu.Unlock()
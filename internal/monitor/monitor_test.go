@@ -0,0 +1,28 @@
+package monitor
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFetchSymbolsIncludeOnlyUsesExchangeList(t *testing.T) {
+	listed := []string{"BTCUSDT", "ETHBTC", "ETHUSDT"}
+	m, _, _ := newTestMonitor(t, &listed)
+
+	symbols, err := m.fetchSymbols()
+	if err != nil {
+		t.Fatalf("fetchSymbols() error = %v", err)
+	}
+	if want := []string{"BTCUSDT", "ETHUSDT"}; !reflect.DeepEqual(symbols, want) {
+		t.Errorf("fetchSymbols() = %v, want %v", symbols, want)
+	}
+
+	m.cfg.Monitoring.IncludeOnly = []string{"ethbtc", "GONEUSDT"}
+	symbols, err = m.fetchSymbols()
+	if err != nil {
+		t.Fatalf("fetchSymbols() error = %v", err)
+	}
+	if want := []string{"ETHBTC"}; !reflect.DeepEqual(symbols, want) {
+		t.Errorf("fetchSymbols() with include_only = %v, want %v", symbols, want)
+	}
+}
@@ -0,0 +1,41 @@
+package monitor
+
+import (
+	"fmt"
+	"html"
+
+	"mexc-monitor/internal/database"
+
+	log "github.com/sirupsen/logrus"
+)
+
+func (m *Monitor) settings() (*database.Settings, error) {
+	settings, err := m.db.GetSettings()
+
+	m.settingsMu.Lock()
+	defer m.settingsMu.Unlock()
+
+	if err == nil {
+		cached := *settings
+		m.lastSettings = &cached
+		if m.settingsDegraded {
+			m.settingsDegraded = false
+			log.Info("Database is readable again, using stored settings")
+			go m.bot.NotifyAdmins("✅ База данных снова доступна")
+		}
+		return settings, nil
+	}
+
+	if m.lastSettings == nil {
+		return nil, err
+	}
+
+	if !m.settingsDegraded {
+		m.settingsDegraded = true
+		log.Warnf("Failed to read settings, falling back to last known good values: %v", err)
+		go m.bot.NotifyAdmins(fmt.Sprintf("⚠️ База данных недоступна, используются последние известные настройки: %s", html.EscapeString(err.Error())))
+	}
+
+	cached := *m.lastSettings
+	return &cached, nil
+}
@@ -0,0 +1,144 @@
+package monitor
+
+import (
+	"sort"
+	"strings"
+
+	"mexc-monitor/internal/mexc"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	sourceREST      = "rest"
+	sourceWebSocket = "ws"
+)
+
+func (m *Monitor) sourceFor(symbol string) string {
+	for configured, source := range m.cfg.Monitoring.DataSources {
+		if strings.EqualFold(configured, symbol) {
+			return strings.ToLower(source)
+		}
+	}
+	if source := strings.ToLower(m.cfg.Monitoring.DefaultSource); source != "" {
+		return source
+	}
+	return sourceREST
+}
+
+func (m *Monitor) splitBySource(symbols []string) ([]string, []string) {
+	var wsSymbols, restSymbols []string
+	for _, symbol := range symbols {
+		switch source := m.sourceFor(symbol); source {
+		case sourceWebSocket:
+			wsSymbols = append(wsSymbols, symbol)
+		case sourceREST:
+			restSymbols = append(restSymbols, symbol)
+		default:
+			log.Warnf("Unknown data source %q for %s, using REST", source, symbol)
+			restSymbols = append(restSymbols, symbol)
+		}
+	}
+	return wsSymbols, restSymbols
+}
+
+func (m *Monitor) activeSources(symbols []string) ([]string, []string) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if !m.wsActive {
+		return nil, symbols
+	}
+
+	var wsSymbols, restSymbols []string
+	for _, symbol := range symbols {
+		if m.wsSymbols[symbol] {
+			wsSymbols = append(wsSymbols, symbol)
+		} else {
+			restSymbols = append(restSymbols, symbol)
+		}
+	}
+	return wsSymbols, restSymbols
+}
+
+func (m *Monitor) restSymbols() []string {
+	_, restSymbols := m.activeSources(m.currentSymbols())
+	return restSymbols
+}
+
+func (m *Monitor) startWebSocket(symbols []string) error {
+	if limit := mexc.MaxStreamsPerConnection / 2; len(symbols) > limit {
+		log.Warnf("MEXC allows %d streams per connection, streaming %d of %d symbols over WebSocket and polling the rest over REST",
+			mexc.MaxStreamsPerConnection, limit, len(symbols))
+		symbols = symbols[:limit]
+	}
+
+	m.client.OnTrade(m.handleTrade)
+	m.client.OnTicker(m.handleTicker)
+
+	if err := m.client.Connect(); err != nil {
+		return err
+	}
+
+	if err := m.client.SubscribeToTrades(symbols); err != nil {
+		log.Errorf("Failed to subscribe to trades: %v", err)
+	}
+	if err := m.client.SubscribeToTickers(symbols); err != nil {
+		log.Errorf("Failed to subscribe to tickers: %v", err)
+	}
+
+	m.mu.Lock()
+	m.wsActive = true
+	m.wsSymbols = make(map[string]bool, len(symbols))
+	for _, symbol := range symbols {
+		m.wsSymbols[symbol] = true
+	}
+	m.mu.Unlock()
+
+	log.Infof("Streaming %d symbols over WebSocket", len(symbols))
+	return nil
+}
+
+func (m *Monitor) updateStreams(symbols []string) {
+	wanted, _ := m.splitBySource(symbols)
+	listed := make(map[string]bool, len(wanted))
+	for _, symbol := range wanted {
+		listed[symbol] = true
+	}
+
+	m.mu.Lock()
+	var removed, added []string
+	for symbol := range m.wsSymbols {
+		if !listed[symbol] {
+			removed = append(removed, symbol)
+			delete(m.wsSymbols, symbol)
+		}
+	}
+	for _, symbol := range wanted {
+		if !m.wsSymbols[symbol] && len(m.wsSymbols) < mexc.MaxStreamsPerConnection/2 {
+			added = append(added, symbol)
+			m.wsSymbols[symbol] = true
+		}
+	}
+	m.mu.Unlock()
+
+	if len(removed) == 0 && len(added) == 0 {
+		return
+	}
+	sort.Strings(removed)
+
+	if err := m.client.UnsubscribeFromTrades(removed); err != nil {
+		log.Errorf("Failed to unsubscribe from trades: %v", err)
+	}
+	if err := m.client.UnsubscribeFromTickers(removed); err != nil {
+		log.Errorf("Failed to unsubscribe from tickers: %v", err)
+	}
+	if err := m.client.SubscribeToTrades(added); err != nil {
+		log.Errorf("Failed to subscribe to trades: %v", err)
+	}
+	if err := m.client.SubscribeToTickers(added); err != nil {
+		log.Errorf("Failed to subscribe to tickers: %v", err)
+	}
+
+	log.Infof("WebSocket streams updated: %d symbols added, %d removed", len(added), len(removed))
+}
@@ -0,0 +1,55 @@
+package monitor
+
+import (
+	"fmt"
+	"strings"
+
+	"mexc-monitor/internal/config"
+
+	log "github.com/sirupsen/logrus"
+)
+
+func (m *Monitor) notifyStartup(symbols []string) {
+	if !m.cfg.Telegram.StartupSummary {
+		return
+	}
+
+	settings, err := m.settings()
+	if err != nil {
+		log.Errorf("Failed to get settings for startup summary: %v", err)
+		return
+	}
+
+	source := "тестовые данные (mock)"
+	if !m.client.IsMock() {
+		wsSymbols, restSymbols := m.activeSources(symbols)
+		source = fmt.Sprintf("WebSocket %d, REST %d", len(wsSymbols), len(restSymbols))
+	}
+
+	volume := fmt.Sprintf("$%d", settings.MinVolume)
+	if settings.MinVolumeUp > 0 || settings.MinVolumeDown > 0 {
+		volume += fmt.Sprintf(" (рост $%d, падение $%d)",
+			settings.VolumeThreshold(1), settings.VolumeThreshold(-1))
+	}
+
+	reference := "за интервал"
+	if m.anchorEnabled() {
+		reference = fmt.Sprintf("от открытия сессии в %s UTC", m.cfg.Monitoring.SessionOpen)
+	}
+
+	var text strings.Builder
+	text.WriteString(fmt.Sprintf("🟢 <b>MEXC Monitor %s запущен</b>\n\n", config.Version))
+	text.WriteString(fmt.Sprintf("Telegram: %s\n", m.cfg.Telegram.Mode))
+	text.WriteString(fmt.Sprintf("Монет: %d (%s)\n", len(symbols), source))
+	text.WriteString(fmt.Sprintf("⏱ Интервал: %d секунд\n", settings.TimeInterval))
+	text.WriteString(fmt.Sprintf("📈 Изменение цены: %.2f%% %s\n", settings.PriceChange, reference))
+	text.WriteString(fmt.Sprintf("💰 Минимальный объем: %s\n", volume))
+	if cooldown := m.cfg.Monitoring.AlertCooldown; cooldown > 0 {
+		text.WriteString(fmt.Sprintf("🔕 Кулдаун: %d секунд\n", cooldown))
+	}
+	if limit := m.cfg.Monitoring.MaxAlertsPerMinute; limit > 0 {
+		text.WriteString(fmt.Sprintf("📋 Не больше %d алертов в минуту\n", limit))
+	}
+
+	m.bot.NotifyAdmins(text.String())
+}
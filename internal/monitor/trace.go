@@ -0,0 +1,46 @@
+package monitor
+
+import (
+	"fmt"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+func (m *Monitor) Trace(symbol string, duration time.Duration) {
+	m.traceMu.Lock()
+	defer m.traceMu.Unlock()
+
+	if duration <= 0 {
+		delete(m.tracedSymbols, symbol)
+		log.Infof("Tracing disabled for %s", symbol)
+		return
+	}
+
+	m.tracedSymbols[symbol] = time.Now().Add(duration)
+	log.Infof("Tracing enabled for %s for %s", symbol, duration)
+}
+
+func (m *Monitor) isTraced(symbol string) bool {
+	m.traceMu.Lock()
+	defer m.traceMu.Unlock()
+
+	expiresAt, ok := m.tracedSymbols[symbol]
+	if !ok {
+		return false
+	}
+
+	if time.Now().After(expiresAt) {
+		delete(m.tracedSymbols, symbol)
+		log.Infof("Tracing expired for %s", symbol)
+		return false
+	}
+	return true
+}
+
+func (m *Monitor) tracef(symbol, format string, args ...interface{}) {
+	if !m.isTraced(symbol) {
+		return
+	}
+	log.Infof("[trace %s] %s", symbol, fmt.Sprintf(format, args...))
+}
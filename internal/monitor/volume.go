@@ -0,0 +1,58 @@
+package monitor
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	volumeFromTrades = "trades"
+	volumeFromTicker = "ticker"
+)
+
+func (m *Monitor) volumeFromTicker() bool {
+	return strings.EqualFold(m.cfg.Monitoring.VolumeSource, volumeFromTicker)
+}
+
+func (m *Monitor) addVolume(symbol string, volumeUSD int) {
+	if volData, exists := m.volumeData[symbol]; exists {
+		volData.Volume += volumeUSD
+		volData.Timestamp = time.Now()
+	} else {
+		m.volumeData[symbol] = &VolumeData{
+			Volume:    volumeUSD,
+			Timestamp: time.Now(),
+		}
+	}
+}
+
+func (m *Monitor) applyTickerVolume(symbol, quoteVolume string) {
+	if quoteVolume == "" {
+		return
+	}
+
+	cumulative, err := strconv.ParseFloat(quoteVolume, 64)
+	if err != nil {
+		log.Errorf("Failed to parse ticker volume for %s: %v", symbol, err)
+		return
+	}
+
+	previous, ok := m.tickerVolumes[symbol]
+	m.tickerVolumes[symbol] = cumulative
+	if !ok {
+		m.tracef(symbol, "ticker 24h volume baseline=$%.0f", cumulative)
+		return
+	}
+
+	if cumulative < previous {
+		m.tracef(symbol, "ticker 24h volume dropped from $%.0f to $%.0f, skipping frame", previous, cumulative)
+		return
+	}
+
+	delta := int(cumulative - previous)
+	m.tracef(symbol, "ticker 24h volume=$%.0f interval=$%d", cumulative, delta)
+	m.addVolume(symbol, delta)
+}
@@ -0,0 +1,43 @@
+package monitor
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const watchdogInterval = 30 * time.Second
+
+func (m *Monitor) watchdogRoutine(ctx context.Context) {
+	timeout := time.Duration(m.cfg.Monitoring.DataTimeout) * time.Second
+	if timeout <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(watchdogInterval)
+	defer ticker.Stop()
+
+	alerted := false
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			m.mu.RLock()
+			silence := time.Since(m.lastDataAt)
+			m.mu.RUnlock()
+
+			if silence >= timeout && !alerted {
+				alerted = true
+				log.Errorf("No market data received for %s", silence.Round(time.Second))
+				m.bot.NotifyAdmins(fmt.Sprintf("🛑 Нет рыночных данных уже %s", silence.Round(time.Second)))
+			} else if silence < timeout && alerted {
+				alerted = false
+				log.Info("Market data feed recovered")
+				m.bot.NotifyAdmins("✅ Рыночные данные снова поступают")
+			}
+		}
+	}
+}
@@ -0,0 +1,42 @@
+package telegram
+
+import (
+	"fmt"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	log "github.com/sirupsen/logrus"
+)
+
+func (b *Bot) handleAnchorCommand(message *tgbotapi.Message, args string) {
+	if b.monitor == nil {
+		b.sendMessage(message.Chat.ID, "Монитор еще не запущен")
+		return
+	}
+
+	switch strings.ToLower(strings.TrimSpace(args)) {
+	case "":
+		enabled, since, symbols := b.monitor.AnchorState()
+		if !enabled {
+			b.sendMessage(message.Chat.ID, "Изменение цены считается за интервал. Чтобы считать от открытия сессии, задайте monitoring.change_reference: session")
+			return
+		}
+		b.sendMessage(message.Chat.ID, fmt.Sprintf("⚓ Изменение цены считается от открытия сессии\n\nЯкорь с: %s UTC\nМонет с якорем: %d\n\nСбросить: /anchor reset",
+			since.Format("2006-01-02 15:04"), symbols))
+
+	case "reset":
+		if !b.requireAdmin(message) {
+			return
+		}
+		count, ok := b.monitor.ResetAnchors()
+		if !ok {
+			b.sendMessage(message.Chat.ID, "Якоря не используются: monitoring.change_reference не равен session")
+			return
+		}
+		log.Infof("Пользователь %d сбросил якоря сессии (%d монет)", message.From.ID, count)
+		b.sendMessage(message.Chat.ID, fmt.Sprintf("⚓ Якоря сброшены для %d монет, новые якоря - по следующей цене каждой монеты", count))
+
+	default:
+		b.sendMessage(message.Chat.ID, "Использование: /anchor - показать якорь сессии, /anchor reset - установить якоря заново")
+	}
+}
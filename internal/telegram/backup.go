@@ -0,0 +1,66 @@
+package telegram
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	log "github.com/sirupsen/logrus"
+)
+
+const maxBackupFileSize = 50 * 1024 * 1024
+
+func (b *Bot) handleBackupCommand(message *tgbotapi.Message) {
+	if !b.requireAdmin(message) {
+		return
+	}
+
+	dir, err := os.MkdirTemp("", "mexc-monitor-backup")
+	if err != nil {
+		log.Errorf("Не удалось создать каталог для резервной копии: %v", err)
+		b.sendMessage(message.Chat.ID, "Ошибка создания резервной копии")
+		return
+	}
+	defer os.RemoveAll(dir)
+
+	now := time.Now().UTC()
+	path := filepath.Join(dir, fmt.Sprintf("mexc-monitor-%s.db", now.Format("20060102-150405")))
+	if err := b.db.Backup(path); err != nil {
+		log.Errorf("Не удалось создать резервную копию базы данных: %v", err)
+		b.sendMessage(message.Chat.ID, "Ошибка создания резервной копии")
+		return
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		log.Errorf("Не удалось прочитать резервную копию базы данных: %v", err)
+		b.sendMessage(message.Chat.ID, "Ошибка создания резервной копии")
+		return
+	}
+	if info.Size() > maxBackupFileSize {
+		b.sendMessage(message.Chat.ID, fmt.Sprintf("Резервная копия занимает %.1f MB, это больше лимита Telegram в 50 MB. "+
+			"Уменьшите историю командой /clearhistory или скопируйте базу с сервера.", float64(info.Size())/1024/1024))
+		return
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		log.Errorf("Не удалось прочитать резервную копию базы данных: %v", err)
+		b.sendMessage(message.Chat.ID, "Ошибка создания резервной копии")
+		return
+	}
+
+	doc := tgbotapi.NewDocument(message.Chat.ID, tgbotapi.FileBytes{Name: filepath.Base(path), Bytes: data})
+	doc.Caption = fmt.Sprintf("💾 Резервная копия базы данных от %s UTC (%.1f MB)",
+		now.Format("2006-01-02 15:04"), float64(info.Size())/1024/1024)
+
+	if err := b.send(message.Chat.ID, doc); err != nil {
+		log.Errorf("Не удалось отправить резервную копию базы данных: %v", err)
+		b.sendMessage(message.Chat.ID, "Ошибка отправки резервной копии")
+		return
+	}
+
+	log.Infof("Пользователь %d выгрузил резервную копию базы данных (%d байт)", message.From.ID, info.Size())
+}
@@ -1,37 +1,106 @@
 package telegram
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
+	"html"
 	"math"
+	"net/http"
+	"sort"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
+	"unicode/utf8"
 
+	"mexc-monitor/internal/config"
 	"mexc-monitor/internal/database"
+	"mexc-monitor/internal/logging"
 
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 	log "github.com/sirupsen/logrus"
 )
 
+const (
+	sendAttempts   = 3
+	sendRetryDelay = 2 * time.Second
+	sendsPerSecond = 25
+
+	maxMessageLength = 4096
+
+	testAlertSymbol = "TEST/USDT"
+	testAlertChange = 2.5
+	testAlertVolume = 15000
+
+	defaultEmojiVolume = 10000
+
+	maintenanceTimeLayout = "2006-01-02T15:04"
+)
+
 type Bot struct {
-	api          *tgbotapi.BotAPI
-	db           *database.Database
-	stopChan     chan struct{}
-	allowedUsers map[int64]bool
+	api           *tgbotapi.BotAPI
+	sender        sender
+	cfg           *config.Config
+	db            database.Store
+	webhookServer *http.Server
+	stopChan      chan struct{}
+	usersMu       sync.RWMutex
+	allowedUsers  map[int64]bool
+	admins        []int64
+	monitor       Monitor
+	sendLimiter   <-chan time.Time
+	commandsMu    sync.Mutex
+	commandTimes  map[int64][]time.Time
+	errorLog      *logging.RingHook
 }
 
-func NewBot(token string, db *database.Database) (*Bot, error) {
-	api, err := tgbotapi.NewBotAPI(token)
+func NewBot(cfg *config.Config, db database.Store) (*Bot, error) {
+	api, err := tgbotapi.NewBotAPI(cfg.Telegram.BotToken)
 	if err != nil {
 		return nil, err
 	}
 
-	return &Bot{
+	bot := &Bot{
 		api:          api,
+		sender:       api,
+		cfg:          cfg,
 		db:           db,
 		stopChan:     make(chan struct{}),
 		allowedUsers: make(map[int64]bool),
-	}, nil
+		admins:       cfg.Telegram.Admins,
+		sendLimiter:  time.Tick(time.Second / sendsPerSecond),
+		commandTimes: make(map[int64][]time.Time),
+	}
+
+	if _, err := bot.loadUsers(); err != nil {
+		return nil, fmt.Errorf("ошибка загрузки пользователей: %v", err)
+	}
+
+	return bot, nil
+}
+
+func (b *Bot) loadUsers() (int, error) {
+	users, err := b.db.GetUsers()
+	if err != nil {
+		return 0, err
+	}
+
+	allowed := make(map[int64]bool, len(users))
+	for _, userID := range users {
+		allowed[userID] = true
+	}
+
+	b.usersMu.Lock()
+	b.allowedUsers = allowed
+	b.usersMu.Unlock()
+
+	log.Infof("Загружено пользователей: %d", len(users))
+	return len(users), nil
+}
+
+func (b *Bot) SetMonitor(monitor Monitor) {
+	b.monitor = monitor
 }
 
 func (b *Bot) Start() error {
@@ -44,16 +113,20 @@ func (b *Bot) Start() error {
 
 	log.Info("✅ Подключение к Telegram API установлено")
 
-	u := tgbotapi.NewUpdate(0)
-	u.Timeout = 60
-
-	updates := b.api.GetUpdatesChan(u)
+	updates, err := b.updatesChannel()
+	if err != nil {
+		return err
+	}
 
 	log.Info("✅ Канал обновлений создан, ожидание сообщений...")
 
 	for {
 		select {
 		case update := <-updates:
+			if update.CallbackQuery != nil {
+				b.handleCallback(update.CallbackQuery)
+				continue
+			}
 			if update.Message == nil {
 				continue
 			}
@@ -63,14 +136,40 @@ func (b *Bot) Start() error {
 
 			if update.Message.IsCommand() {
 				b.handleCommand(update.Message)
+			} else if isImportCaption(update.Message) {
+				b.handleImportWatchlistCommand(update.Message,
+					strings.TrimPrefix(strings.TrimSpace(update.Message.Caption), importCommand))
 			}
 		case <-b.stopChan:
 			log.Info("Получен сигнал остановки бота")
+			if b.webhookServer != nil {
+				b.webhookServer.Close()
+			} else {
+				b.api.StopReceivingUpdates()
+			}
 			return nil
 		}
 	}
 }
 
+func (b *Bot) updatesChannel() (tgbotapi.UpdatesChannel, error) {
+	if b.cfg.Telegram.Mode == "webhook" {
+		log.Info("Режим получения обновлений: webhook")
+		return b.listenWebhook()
+	}
+
+	if _, err := b.api.Request(tgbotapi.DeleteWebhookConfig{}); err != nil {
+		log.Warnf("Не удалось удалить webhook: %v", err)
+	}
+
+	log.Info("Режим получения обновлений: long polling")
+
+	u := tgbotapi.NewUpdate(0)
+	u.Timeout = 60
+
+	return b.api.GetUpdatesChan(u), nil
+}
+
 func (b *Bot) Stop() {
 	close(b.stopChan)
 }
@@ -79,6 +178,12 @@ func (b *Bot) handleCommand(message *tgbotapi.Message) {
 	command := message.Command()
 	args := message.CommandArguments()
 
+	if !b.allowCommand(message.From.ID) {
+		log.Warnf("Пользователь %d превысил лимит команд", message.From.ID)
+		b.sendMessage(message.Chat.ID, "⏳ Слишком много команд. Подождите немного и попробуйте снова.")
+		return
+	}
+
 	switch command {
 	case "start":
 		b.handleStartCommand(message)
@@ -86,12 +191,70 @@ func (b *Bot) handleCommand(message *tgbotapi.Message) {
 		b.handleSetCommand(message, args)
 	case "status":
 		b.handleStatusCommand(message)
+	case "settings":
+		b.handleSettingsCommand(message)
 	case "blacklist":
 		b.handleBlacklistCommand(message, args)
 	case "help":
 		b.handleHelpCommand(message)
 	case "test":
-		b.handleTestCommand(message)
+		b.handleTestCommand(message, args)
+	case "volume":
+		b.handleVolumeCommand(message, args)
+	case "flush":
+		b.handleFlushCommand(message)
+	case "trace":
+		b.handleTraceCommand(message, args)
+	case "alert":
+		b.handleAlertCommand(message, args)
+	case "maintenance":
+		b.handleMaintenanceCommand(message, args)
+	case "why":
+		b.handleWhyCommand(message, args)
+	case "chart":
+		b.handleChartCommand(message, args)
+	case "price":
+		b.handlePriceCommand(message, args)
+	case "symstats":
+		b.handleSymStatsCommand(message, args)
+	case "gainers":
+		b.handleMoversCommand(message, args, true)
+	case "losers":
+		b.handleMoversCommand(message, args, false)
+	case "subscribe":
+		b.handleSubscribeCommand(message, args)
+	case "subscribers":
+		b.handleSubscribersCommand(message)
+	case "refresh":
+		b.handleRefreshCommand(message)
+	case "analyze":
+		b.handleAnalyzeCommand(message)
+	case "clearhistory":
+		b.handleClearHistoryCommand(message, args)
+	case "share":
+		b.handleShareCommand(message)
+	case "apply":
+		b.handleApplyCommand(message, args)
+	case "watch":
+		b.handleWatchCommand(message, args)
+	case "unwatch":
+		b.handleUnwatchCommand(message, args)
+	case "import_watchlist":
+		b.handleImportWatchlistCommand(message, args)
+	case "calm":
+		b.handleCalmCommand(message, args)
+	case "diag":
+		b.handleDiagCommand(message)
+	case "anchor":
+		b.handleAnchorCommand(message, args)
+	case "errors":
+		b.handleErrorsCommand(message, args)
+	case "cleanup_preview":
+		b.handleCleanupPreviewCommand(message)
+	case "backup":
+		b.handleBackupCommand(message)
+	case "reloadusers":
+		b.handleReloadUsersCommand(message)
 	default:
 		b.sendMessage(message.Chat.ID, "Неизвестная команда. Используйте /help для списка доступных команд.")
 	}
@@ -99,14 +262,34 @@ func (b *Bot) handleCommand(message *tgbotapi.Message) {
 
 func (b *Bot) handleSetCommand(message *tgbotapi.Message, args string) {
 	parts := strings.Fields(args)
+	if len(parts) > 0 && parts[0] == "override" {
+		b.handleSetOverride(message, parts[1:])
+		return
+	}
+
 	if len(parts) != 2 {
-		b.sendMessage(message.Chat.ID, "Использование: /set <параметр> <значение>\nПараметры: time, volume, change")
+		b.sendMessage(message.Chat.ID, "Использование: /set &lt;параметр&gt; &lt;значение&gt;\nПараметры: time, volume, volume_up, volume_down, change, retention, format, maxalerts, timezone, sessions, override")
 		return
 	}
 
 	param := parts[0]
 	valueStr := parts[1]
 
+	switch param {
+	case "format":
+		b.handleSetFormat(message, valueStr)
+		return
+	case "maxalerts":
+		b.handleSetMaxAlerts(message, valueStr)
+		return
+	case "timezone":
+		b.handleSetTimezone(message, valueStr)
+		return
+	case "sessions":
+		b.handleSetSessions(message, valueStr)
+		return
+	}
+
 	settings, err := b.db.GetSettings()
 	if err != nil {
 		log.Errorf("Failed to get settings: %v", err)
@@ -114,6 +297,8 @@ func (b *Bot) handleSetCommand(message *tgbotapi.Message, args string) {
 		return
 	}
 
+	var oldValue, newValue string
+
 	switch param {
 	case "time":
 		value, err := strconv.Atoi(valueStr)
@@ -121,6 +306,7 @@ func (b *Bot) handleSetCommand(message *tgbotapi.Message, args string) {
 			b.sendMessage(message.Chat.ID, "Неверное значение времени. Должно быть положительным целым числом.")
 			return
 		}
+		oldValue, newValue = strconv.Itoa(settings.TimeInterval), strconv.Itoa(value)
 		settings.TimeInterval = value
 		b.sendMessage(message.Chat.ID, fmt.Sprintf("Интервал времени установлен на %d секунд", value))
 
@@ -130,20 +316,53 @@ func (b *Bot) handleSetCommand(message *tgbotapi.Message, args string) {
 			b.sendMessage(message.Chat.ID, "Неверное значение объема. Должно быть положительным целым числом.")
 			return
 		}
+		oldValue, newValue = strconv.Itoa(settings.MinVolume), strconv.Itoa(value)
 		settings.MinVolume = value
 		b.sendMessage(message.Chat.ID, fmt.Sprintf("Минимальный объем установлен на $%d", value))
 
+	case "volume_up", "volume_down":
+		value, err := strconv.Atoi(valueStr)
+		if err != nil || value < 0 {
+			b.sendMessage(message.Chat.ID, "Неверное значение объема. Должно быть целым числом не меньше 0 (0 - использовать общий минимальный объем).")
+			return
+		}
+		target, direction := &settings.MinVolumeUp, "роста"
+		if param == "volume_down" {
+			target, direction = &settings.MinVolumeDown, "падения"
+		}
+		oldValue, newValue = strconv.Itoa(*target), strconv.Itoa(value)
+		*target = value
+		if value == 0 {
+			b.sendMessage(message.Chat.ID, fmt.Sprintf("Минимальный объем для %s сброшен, используется общий $%d", direction, settings.MinVolume))
+		} else {
+			b.sendMessage(message.Chat.ID, fmt.Sprintf("Минимальный объем для %s установлен на $%d", direction, value))
+		}
+
 	case "change":
 		value, err := strconv.ParseFloat(valueStr, 64)
 		if err != nil || value <= 0 {
 			b.sendMessage(message.Chat.ID, "Неверное значение изменения. Должно быть положительным числом.")
 			return
 		}
+		oldValue, newValue = formatPrice(settings.PriceChange), formatPrice(value)
 		settings.PriceChange = value
 		b.sendMessage(message.Chat.ID, fmt.Sprintf("Порог изменения цены установлен на %.2f%%", value))
 
+	case "retention":
+		if !b.requireAdmin(message) {
+			return
+		}
+		value, err := strconv.Atoi(valueStr)
+		if err != nil || value <= 0 {
+			b.sendMessage(message.Chat.ID, "Неверное значение хранения. Должно быть положительным целым числом (минуты).")
+			return
+		}
+		oldValue, newValue = strconv.Itoa(settings.Retention), strconv.Itoa(value)
+		settings.Retention = value
+		b.sendMessage(message.Chat.ID, fmt.Sprintf("Хранение истории цен установлено на %d минут", value))
+
 	default:
-		b.sendMessage(message.Chat.ID, "Неизвестный параметр. Доступные: time, volume, change")
+		b.sendMessage(message.Chat.ID, "Неизвестный параметр. Доступные: time, volume, volume_up, volume_down, change, retention, format, maxalerts, timezone, sessions, override")
 		return
 	}
 
@@ -152,6 +371,46 @@ func (b *Bot) handleSetCommand(message *tgbotapi.Message, args string) {
 		b.sendMessage(message.Chat.ID, "Ошибка сохранения настроек")
 		return
 	}
+
+	b.auditSettingChange(message.From, param, oldValue, newValue)
+}
+
+func (b *Bot) auditSettingChange(user *tgbotapi.User, key, oldValue, newValue string) {
+	log.Infof("Пользователь %d изменил настройку %s: %s -> %s", user.ID, key, oldValue, newValue)
+
+	if err := b.db.AddSettingsAudit(user.ID, key, oldValue, newValue); err != nil {
+		log.Errorf("Failed to save settings audit: %v", err)
+	}
+
+	name := user.UserName
+	if name == "" {
+		name = strconv.FormatInt(user.ID, 10)
+	}
+
+	text := fmt.Sprintf("📝 %s изменил настройку <b>%s</b>: %s → %s",
+		html.EscapeString(name), key, oldValue, newValue)
+	for _, adminID := range b.admins {
+		if adminID != user.ID {
+			b.sendMessage(adminID, text)
+		}
+	}
+}
+
+func (b *Bot) handleSetFormat(message *tgbotapi.Message, value string) {
+	value = strings.ToLower(value)
+	if value != "compact" && value != "detailed" {
+		b.sendMessage(message.Chat.ID, "Неверный формат. Доступные: compact, detailed")
+		return
+	}
+
+	ok := b.updateUserSettings(message, func(settings *database.UserSettings) {
+		settings.Format = value
+	})
+	if !ok {
+		return
+	}
+
+	b.sendMessage(message.Chat.ID, fmt.Sprintf("Формат алертов установлен: %s", value))
 }
 
 func (b *Bot) handleStatusCommand(message *tgbotapi.Message) {
@@ -165,187 +424,1237 @@ func (b *Bot) handleStatusCommand(message *tgbotapi.Message) {
 	status := fmt.Sprintf("📊 Текущие настройки:\n\n"+
 		"⏱ Интервал времени: %d секунд\n"+
 		"📈 Изменение цены: %.2f%%\n"+
-		"💰 Минимальный объем: $%d\n",
-		settings.TimeInterval, settings.PriceChange, settings.MinVolume)
+		"💰 Минимальный объем: $%d\n"+
+		"🗄 Хранение истории: %d минут\n",
+		settings.TimeInterval, settings.PriceChange, settings.MinVolume, settings.Retention)
+
+	if settings.MinVolumeUp > 0 {
+		status += fmt.Sprintf("🟢 Минимальный объем для роста: $%d\n", settings.MinVolumeUp)
+	}
+	if settings.MinVolumeDown > 0 {
+		status += fmt.Sprintf("🔴 Минимальный объем для падения: $%d\n", settings.MinVolumeDown)
+	}
 
 	b.sendMessage(message.Chat.ID, status)
 }
 
-func (b *Bot) handleBlacklistCommand(message *tgbotapi.Message, args string) {
-	parts := strings.Fields(args)
-
-	if len(parts) == 0 {
-		entries, err := b.db.GetBlacklist()
-		if err != nil {
-			log.Errorf("Failed to get blacklist: %v", err)
-			b.sendMessage(message.Chat.ID, "Error getting blacklist")
-			return
-		}
-
-		if len(entries) == 0 {
-			b.sendMessage(message.Chat.ID, "Черный список пуст")
-			return
-		}
-
-		var response strings.Builder
-		response.WriteString("🚫 Черный список:\n\n")
-		for _, entry := range entries {
-			remaining := time.Until(entry.ExpiresAt)
-			response.WriteString(fmt.Sprintf("• %s (истекает через %s)\n",
-				entry.Symbol, formatDuration(remaining)))
-		}
-		b.sendMessage(message.Chat.ID, response.String())
+func (b *Bot) handleSettingsCommand(message *tgbotapi.Message) {
+	settings, err := b.db.GetSettings()
+	if err != nil {
+		log.Errorf("Failed to get settings: %v", err)
+		b.sendMessage(message.Chat.ID, "Ошибка получения настроек")
 		return
 	}
 
-	if len(parts) != 2 {
-		b.sendMessage(message.Chat.ID, "Использование: /blacklist <символ> <длительность_в_секундах>\nПример: /blacklist BTC 3600")
+	data, err := json.MarshalIndent(settings, "", "  ")
+	if err != nil {
+		log.Errorf("Failed to marshal settings: %v", err)
+		b.sendMessage(message.Chat.ID, "Ошибка кодирования настроек")
 		return
 	}
 
-	symbol := strings.ToUpper(parts[0])
-	durationStr := parts[1]
+	b.sendMessage(message.Chat.ID, fmt.Sprintf("<pre>%s</pre>", html.EscapeString(string(data))))
+}
 
-	duration, err := strconv.Atoi(durationStr)
-	if err != nil || duration <= 0 {
-		b.sendMessage(message.Chat.ID, "Неверная длительность. Должно быть положительным целым числом (секунды).")
+func (b *Bot) handleVolumeCommand(message *tgbotapi.Message, args string) {
+	if b.monitor == nil {
+		b.sendMessage(message.Chat.ID, "Монитор еще не запущен")
 		return
 	}
 
-	if err := b.db.AddToBlacklist(symbol, time.Duration(duration)*time.Second); err != nil {
-		log.Errorf("Failed to add to blacklist: %v", err)
-		b.sendMessage(message.Chat.ID, "Ошибка добавления в черный список")
+	limit, ok := parseLimit(args)
+	if !ok {
+		b.sendMessage(message.Chat.ID, "Использование: /volume [количество от 1 до 50]")
 		return
 	}
 
-	b.sendMessage(message.Chat.ID, fmt.Sprintf("Добавлено %s в черный список на %s",
-		symbol, formatDuration(time.Duration(duration)*time.Second)))
-}
-
-func (b *Bot) handleStartCommand(message *tgbotapi.Message) {
-	b.AddUser(message.Chat.ID)
+	volumes := b.monitor.TopVolumes(limit)
+	if len(volumes) == 0 {
+		b.sendMessage(message.Chat.ID, "Нет данных об объемах")
+		return
+	}
 
-	welcomeMsg := `🤖 Добро пожаловать в MEXC Monitor Bot!
+	var response strings.Builder
+	response.WriteString("💰 Топ по объему за интервал:\n\n")
+	for i, item := range volumes {
+		response.WriteString(fmt.Sprintf("%d. %s - %s\n", i+1, item.Symbol, b.formatMoney(item.Volume)))
+	}
+	b.sendLongMessage(message.Chat.ID, response.String())
+}
 
-Этот бот отслеживает цены и объемы криптовалют на бирже MEXC и отправляет уведомления при значительных изменениях.
+func (b *Bot) handleMoversCommand(message *tgbotapi.Message, args string, gainers bool) {
+	if b.monitor == nil {
+		b.sendMessage(message.Chat.ID, "Монитор еще не запущен")
+		return
+	}
 
-Доступные команды:
-• /start - Запустить бота и получать алерты
-• /status - Показать текущие настройки
-• /set time (секунды) - Установить интервал мониторинга
-• /set volume (сумма) - Установить минимальный объем
-• /set change (процент) - Установить порог изменения цены
-• /blacklist (символ) (секунды) - Добавить монету в черный список
-• /blacklist - Показать черный список
-• /help - Показать справку
-• /test - Отправить тестовый алерт
+	command, title, empty := "/gainers", "🟢 Лидеры роста за интервал:", "Нет растущих монет"
+	if !gainers {
+		command, title, empty = "/losers", "🔴 Лидеры падения за интервал:", "Нет падающих монет"
+	}
 
-Примеры:
-/set time 5
-/set volume 5000
-/set change 2.5`
+	limit, ok := parseLimit(args)
+	if !ok {
+		b.sendMessage(message.Chat.ID, fmt.Sprintf("Использование: %s [количество от 1 до 50]", command))
+		return
+	}
 
-	b.sendMessage(message.Chat.ID, welcomeMsg)
+	movers := b.monitor.TopMovers(limit, gainers)
+	if len(movers) == 0 {
+		b.sendMessage(message.Chat.ID, empty)
+		return
+	}
 
-	go func() {
-		time.Sleep(2 * time.Second)
-		b.SendAlert("TEST/USDT", 2.5, 15000, time.Now())
-	}()
+	var response strings.Builder
+	response.WriteString(title + "\n\n")
+	for i, item := range movers {
+		response.WriteString(fmt.Sprintf("%d. %s %+.2f%%\n", i+1, item.Symbol, item.Change))
+	}
+	b.sendLongMessage(message.Chat.ID, response.String())
 }
 
-func (b *Bot) handleHelpCommand(message *tgbotapi.Message) {
-	helpMsg := `📋 Команды MEXC Monitor Bot:
+func (b *Bot) handleAlertCommand(message *tgbotapi.Message, args string) {
+	parts := strings.Fields(args)
+	usage := "Использование:\n/alert - список ваших ценовых уровней\n" +
+		"/alert &lt;символ&gt; &gt; &lt;цена&gt; [repeat] - уведомить, когда цена поднимется до уровня\n" +
+		"/alert &lt;символ&gt; &lt; &lt;цена&gt; [repeat] - уведомить, когда цена опустится до уровня\n" +
+		"/alert remove &lt;id&gt; - удалить уровень"
 
-🔧 Настройки:
-• /set time (секунды) - Установить интервал мониторинга (по умолчанию: 5)
-• /set volume (сумма) - Установить минимальный объем в USD (по умолчанию: 5000)
-• /set change (процент) - Установить порог изменения цены (по умолчанию: 2.0)
+	if len(parts) == 0 {
+		alerts, err := b.db.GetPriceAlerts(message.Chat.ID)
+		if err != nil {
+			log.Errorf("Failed to get price alerts: %v", err)
+			b.sendMessage(message.Chat.ID, "Ошибка получения ценовых уровней")
+			return
+		}
 
-📊 Информация:
-• /status - Показать текущие настройки
-• /blacklist - Показать черный список монет
+		if len(alerts) == 0 {
+			b.sendMessage(message.Chat.ID, "У вас нет ценовых уровней")
+			return
+		}
 
-🚫 Управление черным списком:
-• /blacklist (символ) (секунды) - Добавить монету в черный список на указанное время
-• Пример: /blacklist BTC 3600 (заблокировать BTC на 1 час)
+		var response strings.Builder
+		response.WriteString("🎯 Ваши ценовые уровни:\n\n")
+		for _, alert := range alerts {
+			direction := "&lt;"
+			if alert.Above {
+				direction = "&gt;"
+			}
+			suffix := ""
+			if alert.Repeat {
+				suffix = " (повтор)"
+			}
+			response.WriteString(fmt.Sprintf("#%d: %s %s %s%s\n",
+				alert.ID, alert.Symbol, direction, formatPrice(alert.Level), suffix))
+		}
+		b.sendLongMessage(message.Chat.ID, response.String())
+		return
+	}
 
-📈 Алерты:
-Алерты отправляются когда:
-- Цена изменяется на указанный процент в течение интервала времени
-- Объем торгов превышает минимальный порог
-- Монета не находится в черном списке
+	if parts[0] == "remove" {
+		if len(parts) != 2 {
+			b.sendMessage(message.Chat.ID, usage)
+			return
+		}
 
-Примеры использования:
-/set time 10
-/set volume 10000
-/set change 3.0
-/blacklist DOGE 1800`
+		id, err := strconv.ParseInt(parts[1], 10, 64)
+		if err != nil {
+			b.sendMessage(message.Chat.ID, "Неверный id уровня")
+			return
+		}
 
-	b.sendMessage(message.Chat.ID, helpMsg)
-}
+		removed, err := b.db.RemovePriceAlert(message.Chat.ID, id)
+		if err != nil {
+			log.Errorf("Failed to remove price alert: %v", err)
+			b.sendMessage(message.Chat.ID, "Ошибка удаления ценового уровня")
+			return
+		}
+		if !removed {
+			b.sendMessage(message.Chat.ID, fmt.Sprintf("Уровень #%d не найден", id))
+			return
+		}
 
-func (b *Bot) handleTestCommand(message *tgbotapi.Message) {
-	b.sendMessage(message.Chat.ID, "🧪 Отправка тестового алерта...")
+		b.sendMessage(message.Chat.ID, fmt.Sprintf("Уровень #%d удален", id))
+		return
+	}
 
-	if err := b.SendAlert("TEST/USDT", 2.5, 15000, time.Now()); err != nil {
-		b.sendMessage(message.Chat.ID, "❌ Не удалось отправить тестовый алерт")
-	} else {
-		b.sendMessage(message.Chat.ID, "✅ Тестовый алерт отправлен успешно!")
+	if len(parts) < 3 || len(parts) > 4 || (len(parts) == 4 && parts[3] != "repeat") {
+		b.sendMessage(message.Chat.ID, usage)
+		return
 	}
-}
 
-func (b *Bot) SendAlert(symbol string, priceChange float64, volume int, timestamp time.Time) error {
-	message := formatAlertMessage(symbol, priceChange, volume, timestamp)
+	symbol, ok := b.resolveSymbol(message.Chat.ID, parts[0])
+	if !ok {
+		return
+	}
+	if !isValidSymbol(symbol) {
+		b.sendMessage(message.Chat.ID, "Неверный символ")
+		return
+	}
 
-	log.Infof("Отправка алерта %d пользователям", len(b.allowedUsers))
+	var above bool
+	switch parts[1] {
+	case ">":
+		above = true
+	case "<":
+		above = false
+	default:
+		b.sendMessage(message.Chat.ID, usage)
+		return
+	}
 
-	for userID := range b.allowedUsers {
-		msg := tgbotapi.NewMessage(userID, message)
-		msg.ParseMode = "HTML"
+	level, err := strconv.ParseFloat(parts[2], 64)
+	if err != nil || level <= 0 {
+		b.sendMessage(message.Chat.ID, "Неверная цена. Должно быть положительным числом.")
+		return
+	}
 
-		if _, err := b.api.Send(msg); err != nil {
-			log.Errorf("Не удалось отправить алерт пользователю %d: %v", userID, err)
-		} else {
-			log.Infof("Успешно отправлен алерт пользователю %d", userID)
-		}
+	alert := &database.PriceAlert{
+		ChatID: message.Chat.ID,
+		Symbol: symbol,
+		Above:  above,
+		Level:  level,
+		Repeat: len(parts) == 4,
 	}
 
-	if len(b.allowedUsers) == 0 {
-		log.Warn("Нет пользователей в списке разрешенных. Отправьте /start боту сначала!")
+	id, err := b.db.AddPriceAlert(alert)
+	if err != nil {
+		log.Errorf("Failed to add price alert: %v", err)
+		b.sendMessage(message.Chat.ID, "Ошибка добавления ценового уровня")
+		return
 	}
 
-	return nil
+	b.sendMessage(message.Chat.ID, fmt.Sprintf("🎯 Уровень #%d добавлен: %s %s %s",
+		id, symbol, parts[1], formatPrice(level)))
 }
 
-func (b *Bot) AddUser(userID int64) {
-	b.allowedUsers[userID] = true
-	log.Infof("Добавлен пользователь %d в список разрешенных", userID)
-}
+func (b *Bot) handleMaintenanceCommand(message *tgbotapi.Message, args string) {
+	parts := strings.Fields(args)
+	usage := "Использование:\n/maintenance - список окон обслуживания\n" +
+		"/maintenance add &lt;начало&gt; &lt;конец&gt; - добавить окно (UTC, формат 2006-01-02T15:04)\n" +
+		"/maintenance remove &lt;id&gt; - удалить окно"
 
-func (b *Bot) RemoveUser(userID int64) {
-	delete(b.allowedUsers, userID)
-	log.Infof("Удален пользователь %d из списка разрешенных", userID)
-}
+	if len(parts) == 0 {
+		windows, err := b.db.GetMaintenanceWindows()
+		if err != nil {
+			log.Errorf("Failed to get maintenance windows: %v", err)
+			b.sendMessage(message.Chat.ID, "Ошибка получения окон обслуживания")
+			return
+		}
 
-func (b *Bot) sendMessage(chatID int64, text string) {
-	msg := tgbotapi.NewMessage(chatID, text)
-	msg.ParseMode = "HTML"
+		if len(windows) == 0 {
+			b.sendMessage(message.Chat.ID, "Запланированных окон обслуживания нет")
+			return
+		}
 
-	if _, err := b.api.Send(msg); err != nil {
-		log.Errorf("Failed to send message: %v", err)
+		var response strings.Builder
+		response.WriteString("🛠 Окна обслуживания (UTC):\n\n")
+		for _, window := range windows {
+			response.WriteString(fmt.Sprintf("#%d: %s - %s\n", window.ID,
+				window.StartsAt.UTC().Format(maintenanceTimeLayout), window.EndsAt.UTC().Format(maintenanceTimeLayout)))
+		}
+		b.sendLongMessage(message.Chat.ID, response.String())
+		return
 	}
-}
 
-func formatAlertMessage(symbol string, priceChange float64, volume int, timestamp time.Time) string {
-	priceChangeStr := fmt.Sprintf("%.2f%%", priceChange)
+	if !b.requireAdmin(message) {
+		return
+	}
+
+	switch parts[0] {
+	case "add":
+		if len(parts) != 3 {
+			b.sendMessage(message.Chat.ID, usage)
+			return
+		}
+
+		startsAt, err := time.Parse(maintenanceTimeLayout, parts[1])
+		if err != nil {
+			b.sendMessage(message.Chat.ID, "Неверное время начала. Формат: 2006-01-02T15:04")
+			return
+		}
+		endsAt, err := time.Parse(maintenanceTimeLayout, parts[2])
+		if err != nil {
+			b.sendMessage(message.Chat.ID, "Неверное время окончания. Формат: 2006-01-02T15:04")
+			return
+		}
+		if !endsAt.After(startsAt) || endsAt.Before(time.Now()) {
+			b.sendMessage(message.Chat.ID, "Окончание должно быть позже начала и в будущем")
+			return
+		}
+
+		id, err := b.db.AddMaintenanceWindow(startsAt, endsAt)
+		if err != nil {
+			log.Errorf("Failed to add maintenance window: %v", err)
+			b.sendMessage(message.Chat.ID, "Ошибка добавления окна обслуживания")
+			return
+		}
+
+		b.sendMessage(message.Chat.ID, fmt.Sprintf("🛠 Окно обслуживания #%d добавлено: %s - %s UTC",
+			id, parts[1], parts[2]))
+
+	case "remove":
+		if len(parts) != 2 {
+			b.sendMessage(message.Chat.ID, usage)
+			return
+		}
+
+		id, err := strconv.ParseInt(parts[1], 10, 64)
+		if err != nil {
+			b.sendMessage(message.Chat.ID, "Неверный id окна обслуживания")
+			return
+		}
+
+		removed, err := b.db.RemoveMaintenanceWindow(id)
+		if err != nil {
+			log.Errorf("Failed to remove maintenance window: %v", err)
+			b.sendMessage(message.Chat.ID, "Ошибка удаления окна обслуживания")
+			return
+		}
+		if !removed {
+			b.sendMessage(message.Chat.ID, fmt.Sprintf("Окно обслуживания #%d не найдено", id))
+			return
+		}
+
+		b.sendMessage(message.Chat.ID, fmt.Sprintf("Окно обслуживания #%d удалено", id))
+
+	default:
+		b.sendMessage(message.Chat.ID, usage)
+	}
+}
+
+func (b *Bot) handleTraceCommand(message *tgbotapi.Message, args string) {
+	if !b.requireAdmin(message) {
+		return
+	}
+
+	if b.monitor == nil {
+		b.sendMessage(message.Chat.ID, "Монитор еще не запущен")
+		return
+	}
+
+	parts := strings.Fields(args)
+	if len(parts) < 1 || len(parts) > 2 {
+		b.sendMessage(message.Chat.ID, "Использование: /trace &lt;символ&gt; [минуты]\n0 минут - отключить трассировку")
+		return
+	}
+
+	symbol, ok := b.resolveSymbol(message.Chat.ID, parts[0])
+	if !ok {
+		return
+	}
+	if !isValidSymbol(symbol) {
+		b.sendMessage(message.Chat.ID, "Неверный символ")
+		return
+	}
+
+	minutes := 5
+	if len(parts) == 2 {
+		value, err := strconv.Atoi(parts[1])
+		if err != nil || value < 0 || value > 60 {
+			b.sendMessage(message.Chat.ID, "Неверная длительность. Должно быть от 0 до 60 минут.")
+			return
+		}
+		minutes = value
+	}
+
+	b.monitor.Trace(symbol, time.Duration(minutes)*time.Minute)
+
+	if minutes == 0 {
+		b.sendMessage(message.Chat.ID, fmt.Sprintf("🔍 Трассировка %s отключена", symbol))
+		return
+	}
+	b.sendMessage(message.Chat.ID, fmt.Sprintf("🔍 Подробное логирование %s включено на %d минут", symbol, minutes))
+}
+
+func (b *Bot) handleWhyCommand(message *tgbotapi.Message, args string) {
+	if b.monitor == nil {
+		b.sendMessage(message.Chat.ID, "Монитор еще не запущен")
+		return
+	}
+
+	symbol, ok := b.resolveSymbol(message.Chat.ID, args)
+	if !ok {
+		return
+	}
+	if !isValidSymbol(symbol) {
+		b.sendMessage(message.Chat.ID, "Использование: /why &lt;символ&gt;\nПример: /why BTCUSDT")
+		return
+	}
+
+	report := b.monitor.Explain(symbol)
+	if report.Skip == SkipNoHistory {
+		b.sendMessage(message.Chat.ID, fmt.Sprintf("Нет данных о цене для %s", symbol))
+		return
+	}
+
+	var response strings.Builder
+	response.WriteString(fmt.Sprintf("🔎 <b>%s</b>\n\n", symbol))
+	response.WriteString(fmt.Sprintf("💵 Текущая цена: %s\n", formatPrice(report.CurrentPrice)))
+
+	if report.ChangeOK {
+		response.WriteString(fmt.Sprintf("✅ Изменение %+.2f%% достигает порога %.2f%%\n",
+			report.PriceChange, report.ChangeThreshold))
+	} else {
+		response.WriteString(fmt.Sprintf("❌ Изменение %+.2f%% не достигает порога %.2f%%\n",
+			report.PriceChange, report.ChangeThreshold))
+	}
+
+	switch {
+	case report.BaselineEMA:
+		response.WriteString("📐 Изменение считается от EMA (ema_alpha)\n")
+	case report.BaselineAge < report.Interval:
+		response.WriteString(fmt.Sprintf("⚠️ Базовая цена взята %s назад, это меньше интервала %s: истории пока недостаточно\n",
+			report.BaselineAge.Round(time.Second), report.Interval))
+	default:
+		response.WriteString(fmt.Sprintf("📐 Базовая цена взята %s назад (интервал %s)\n",
+			report.BaselineAge.Round(time.Second), report.Interval))
+	}
+
+	switch {
+	case report.Pinned:
+		response.WriteString(fmt.Sprintf("✅ Объем %s (порог не применяется для этой пары)\n", b.formatMoney(report.Volume)))
+	case report.VolumeOK:
+		response.WriteString(fmt.Sprintf("✅ Объем %s ≥ %s\n", b.formatMoney(report.Volume), b.formatMoney(report.MinVolume)))
+	default:
+		response.WriteString(fmt.Sprintf("❌ Объем %s &lt; %s\n", b.formatMoney(report.Volume), b.formatMoney(report.MinVolume)))
+	}
+
+	if reason := skipReasonText(report.Skip); reason != "" {
+		response.WriteString(fmt.Sprintf("⏸ %s\n", reason))
+	}
+
+	if report.Skip == SkipNone && report.ChangeOK && report.VolumeOK {
+		response.WriteString("\nВсе условия выполнены - алерт будет отправлен при следующем анализе")
+	}
+
+	b.sendMessage(message.Chat.ID, response.String())
+}
+
+func skipReasonText(reason SkipReason) string {
+	switch reason {
+	case SkipStale:
+		return "Цены по паре давно не обновлялись"
+	case SkipPriceTooOld:
+		return "Последняя цена старше интервала анализа"
+	case SkipCooldown:
+		return "Пара на паузе после недавнего алерта"
+	case SkipBlacklisted:
+		return "Пара в черном списке"
+	case SkipNoVolume:
+		return "Нет свежих данных об объеме"
+	}
+	return ""
+}
+
+func (b *Bot) handleFlushCommand(message *tgbotapi.Message) {
+	if !b.requireAdmin(message) {
+		return
+	}
+
+	if b.monitor == nil {
+		b.sendMessage(message.Chat.ID, "Монитор еще не запущен")
+		return
+	}
+
+	cleared := b.monitor.Reset()
+	log.Infof("Пользователь %d сбросил историю цен и объемов (%d символов)", message.From.ID, cleared)
+	b.sendMessage(message.Chat.ID, fmt.Sprintf("🧹 История цен и объемов очищена для %d символов", cleared))
+}
+
+func (b *Bot) handleRefreshCommand(message *tgbotapi.Message) {
+	if !b.requireAdmin(message) {
+		return
+	}
+
+	if b.monitor == nil {
+		b.sendMessage(message.Chat.ID, "Монитор еще не запущен")
+		return
+	}
+
+	added, removed, err := b.monitor.RefreshSymbols()
+	if err != nil {
+		log.Errorf("Не удалось обновить список символов: %v", err)
+		b.sendMessage(message.Chat.ID, "Ошибка обновления списка символов")
+		return
+	}
+
+	log.Infof("Пользователь %d обновил список символов: +%d, -%d", message.From.ID, added, removed)
+	b.sendMessage(message.Chat.ID, fmt.Sprintf("🔄 Список символов обновлен: добавлено %d, удалено %d", added, removed))
+}
+
+func (b *Bot) handleAnalyzeCommand(message *tgbotapi.Message) {
+	if !b.requireAdmin(message) {
+		return
+	}
+
+	if b.monitor == nil {
+		b.sendMessage(message.Chat.ID, "Монитор еще не запущен")
+		return
+	}
+
+	alerts := b.monitor.AnalyzeNow()
+	log.Infof("Пользователь %d запустил анализ вручную: %d алертов", message.From.ID, alerts)
+	b.sendMessage(message.Chat.ID, fmt.Sprintf("🔬 Анализ выполнен, алертов: %d", alerts))
+}
+
+func (b *Bot) handleClearHistoryCommand(message *tgbotapi.Message, args string) {
+	if !b.requireAdmin(message) {
+		return
+	}
+
+	days, err := strconv.Atoi(strings.TrimSpace(args))
+	if err != nil || days < 0 {
+		b.sendMessage(message.Chat.ID, "Использование: /clearhistory &lt;дни&gt;\nПример: /clearhistory 30 - удалить алерты старше 30 дней")
+		return
+	}
+
+	pruned, err := b.db.PruneAlerts(time.Now().AddDate(0, 0, -days))
+	if err != nil {
+		log.Errorf("Failed to prune alerts: %v", err)
+		b.sendMessage(message.Chat.ID, "Ошибка очистки истории алертов")
+		return
+	}
+
+	log.Infof("Пользователь %d удалил %d алертов старше %d дней", message.From.ID, pruned, days)
+	b.sendMessage(message.Chat.ID, fmt.Sprintf("🗑 Удалено записей истории алертов: %d", pruned))
+}
+
+func (b *Bot) handleSubscribersCommand(message *tgbotapi.Message) {
+	if !b.requireAdmin(message) {
+		return
+	}
+
+	users := b.users()
+	if len(users) == 0 {
+		b.sendMessage(message.Chat.ID, "Подписчиков пока нет")
+		return
+	}
+
+	sort.Slice(users, func(i, j int) bool { return users[i] < users[j] })
+
+	text := fmt.Sprintf("👥 <b>Подписчики (%d):</b>\n\n", len(users))
+	for _, userID := range users {
+		text += fmt.Sprintf("• <code>%d</code>\n", userID)
+	}
+
+	b.sendLongMessage(message.Chat.ID, text)
+}
+
+func (b *Bot) handleBlacklistCommand(message *tgbotapi.Message, args string) {
+	parts := strings.Fields(args)
+
+	if len(parts) == 0 {
+		entries, err := b.db.GetBlacklist()
+		if err != nil {
+			log.Errorf("Failed to get blacklist: %v", err)
+			b.sendMessage(message.Chat.ID, "Error getting blacklist")
+			return
+		}
+
+		if len(entries) == 0 {
+			b.sendMessage(message.Chat.ID, "Черный список пуст")
+			return
+		}
+
+		var response strings.Builder
+		response.WriteString("🚫 Черный список:\n\n")
+		for _, entry := range entries {
+			if entry.Permanent {
+				response.WriteString(fmt.Sprintf("• %s (постоянно)\n", entry.Symbol))
+				continue
+			}
+			remaining := time.Until(entry.ExpiresAt)
+			response.WriteString(fmt.Sprintf("• %s (истекает через %s)\n",
+				entry.Symbol, formatDuration(remaining)))
+		}
+		b.sendLongMessage(message.Chat.ID, response.String())
+		return
+	}
+
+	if strings.ToLower(parts[0]) == "status" {
+		b.handleBlacklistStatus(message, parts[1:])
+		return
+	}
+
+	if len(parts) < 2 {
+		b.sendMessage(message.Chat.ID, "Использование: /blacklist &lt;символ&gt;[,&lt;символ&gt;...] &lt;длительность_в_секундах&gt;\nПример: /blacklist BTC 3600\nПример: /blacklist BTC,ETH,DOGE 3600")
+		return
+	}
+
+	durationStr := parts[len(parts)-1]
+
+	duration, err := strconv.Atoi(durationStr)
+	if err != nil || duration <= 0 {
+		b.sendMessage(message.Chat.ID, "Неверная длительность. Должно быть положительным целым числом (секунды).")
+		return
+	}
+
+	symbols, invalid := parseSymbolList(strings.Join(parts[:len(parts)-1], " "))
+	symbols, ambiguous := b.resolveSymbols(symbols)
+	if len(symbols) == 0 {
+		if len(ambiguous) > 0 {
+			b.sendMessage(message.Chat.ID, "❓ Монета торгуется в нескольких парах, уточните:\n"+strings.Join(ambiguous, "\n"))
+			return
+		}
+		b.sendMessage(message.Chat.ID, "Не указано ни одного корректного символа")
+		return
+	}
+
+	if err := b.db.AddManyToBlacklist(symbols, time.Duration(duration)*time.Second); err != nil {
+		log.Errorf("Failed to add to blacklist: %v", err)
+		b.sendMessage(message.Chat.ID, "Ошибка добавления в черный список")
+		return
+	}
+
+	if len(symbols) == 1 && len(invalid) == 0 && len(ambiguous) == 0 {
+		b.sendMessage(message.Chat.ID, fmt.Sprintf("Добавлено %s в черный список на %s",
+			symbols[0], formatDuration(time.Duration(duration)*time.Second)))
+		return
+	}
+
+	var response strings.Builder
+	response.WriteString(fmt.Sprintf("🚫 Добавлено в черный список на %s:\n\n",
+		formatDuration(time.Duration(duration)*time.Second)))
+	for _, symbol := range symbols {
+		response.WriteString(fmt.Sprintf("✅ %s\n", symbol))
+	}
+	for _, symbol := range invalid {
+		response.WriteString(fmt.Sprintf("❌ %s (неверный символ)\n", html.EscapeString(symbol)))
+	}
+	for _, symbol := range ambiguous {
+		response.WriteString(fmt.Sprintf("❓ %s\n", symbol))
+	}
+	b.sendLongMessage(message.Chat.ID, response.String())
+}
+
+func (b *Bot) handleBlacklistStatus(message *tgbotapi.Message, args []string) {
+	if len(args) != 1 || !isValidSymbol(strings.ToUpper(args[0])) {
+		b.sendMessage(message.Chat.ID, "Использование: /blacklist status &lt;символ&gt;\nПример: /blacklist status BTCUSDT")
+		return
+	}
+	symbol, ok := b.resolveSymbol(message.Chat.ID, args[0])
+	if !ok {
+		return
+	}
+
+	expiresAt, blacklisted, err := b.db.GetBlacklistExpiry(symbol)
+	if err != nil {
+		log.Errorf("Failed to check blacklist: %v", err)
+		b.sendMessage(message.Chat.ID, "Ошибка проверки черного списка")
+		return
+	}
+
+	if !blacklisted {
+		b.sendMessage(message.Chat.ID, fmt.Sprintf("✅ %s не в черном списке", symbol))
+		return
+	}
+
+	if expiresAt.IsZero() {
+		b.sendMessage(message.Chat.ID, fmt.Sprintf("🚫 %s в черном списке постоянно (default_blacklist)", symbol))
+		return
+	}
+
+	b.sendMessage(message.Chat.ID, fmt.Sprintf("🚫 %s в черном списке (истекает через %s)",
+		symbol, formatDuration(time.Until(expiresAt))))
+}
+
+func (b *Bot) handleStartCommand(message *tgbotapi.Message) {
+	b.AddUser(message.Chat.ID)
+
+	welcomeMsg := `🤖 Добро пожаловать в MEXC Monitor Bot!
+
+Этот бот отслеживает цены и объемы криптовалют на бирже MEXC и отправляет уведомления при значительных изменениях.
+
+Доступные команды:
+• /start - Запустить бота и получать алерты
+• /status - Показать текущие настройки
+• /set time (секунды) - Установить интервал мониторинга
+• /set volume (сумма) - Установить минимальный объем
+• /set change (процент) - Установить порог изменения цены
+• /blacklist (символ) (секунды) - Добавить монету в черный список
+• /blacklist - Показать черный список
+• /help - Показать справку
+• /test [изменение] [объем] - Отправить тестовый алерт (по умолчанию: 2.5% и 15000)
+
+Примеры:
+/set time 5
+/set volume 5000
+/set change 2.5`
+
+	b.sendMessage(message.Chat.ID, welcomeMsg)
+
+	go func() {
+		time.Sleep(2 * time.Second)
+		b.SendAlert(testAlertSymbol, testAlertChange, testAlertVolume, time.Now())
+	}()
+}
+
+func (b *Bot) handleHelpCommand(message *tgbotapi.Message) {
+	helpMsg := `📋 Команды MEXC Monitor Bot:
+
+🔧 Настройки:
+• /set time (секунды) - Установить интервал мониторинга (по умолчанию: 5)
+• /set volume (сумма) - Установить минимальный объем в USD (по умолчанию: 5000)
+• /set volume_up (сумма) / volume_down (сумма) - Отдельный минимальный объем для роста и падения (0 - использовать общий)
+• /set change (процент) - Установить порог изменения цены (по умолчанию: 2.0)
+• /set format (compact|detailed) - Выбрать формат своих алертов (по умолчанию: detailed)
+• /set maxalerts (число) - Ограничить количество своих алертов в день (0 - без ограничения)
+• /set timezone (пояс) - Часовой пояс для дневного лимита, например Europe/Moscow (по умолчанию: UTC)
+• /set sessions (сессии) - Получать алерты только в торговые сессии UTC: asia, eu, us или 08:00-12:00, через запятую (off - круглосуточно)
+• /set retention (минуты) - Установить время хранения истории цен, только для администраторов (по умолчанию: 10)
+• /set override (символ) cooldown (секунды) - Отдельный кулдаун алертов для монеты (0 - общий), /set override - показать
+• /subscribe (типы) - Выбрать типы алертов через запятую: move, sustained, acceleration, candles, level, delist (all - все)
+
+📊 Информация:
+• /status - Показать текущие настройки
+• /settings - Показать текущие настройки в формате JSON
+• /share - Получить код текущих настроек, чтобы поделиться ими
+• /blacklist - Показать черный список монет
+• /volume [N] - Показать топ N монет по объему за интервал (по умолчанию: 10)
+• /price (символ) - Показать текущую цену монеты на MEXC
+• /symstats (символ) - Показать статистику алертов, кулдаун и черный список монеты
+• /anchor - Показать, от какого момента считается изменение цены при change_reference: session
+• /why (символ) - Показать, какие условия алерта не выполнены для монеты
+• /chart (символ) [ma] - Показать график цены, ma добавляет скользящую среднюю (например, ma10)
+• /gainers [N] - Показать топ N растущих монет за интервал
+• /watch (символ) - Добавить монету в свой список наблюдения, /watch - показать список
+• /unwatch (символ) - Убрать монету из списка наблюдения
+• /import_watchlist (символы) - Добавить много монет сразу (через запятую или с новой строки, либо файлом с этой подписью)
+• /losers [N] - Показать топ N падающих монет за интервал
+
+🛠 Администрирование:
+• /flush - Очистить историю цен и объемов
+• /subscribers - Показать подписчиков на алерты
+• /refresh - Перечитать список отслеживаемых монет
+• /analyze - Запустить анализ немедленно
+• /diag - Показать горутины, память и объем хранимой истории
+• /anchor reset - Заново установить якоря сессии по текущим ценам (при change_reference: session)
+• /errors [N] - Показать последние N ошибок и предупреждений из лога (по умолчанию: 10)
+• /cleanup_preview - Показать, сколько истории цен и объемов удалит следующая очистка
+• /backup - Прислать резервную копию базы данных файлом
+• /reloadusers - Перечитать список подписчиков из базы данных
+• /calm (множитель) (минуты) - Временно увеличить пороги изменения цены и объема, /calm off - отменить
+• /clearhistory (дни) - Удалить историю алертов старше указанного числа дней
+• /apply (код) - Применить настройки из кода /share
+• /trace (символ) [минуты] - Временно включить подробное логирование одной монеты (по умолчанию: 5 минут)
+• /maintenance - Показать окна обслуживания MEXC
+• /maintenance add (начало) (конец) - Запланировать окно без алертов (UTC, 2006-01-02T15:04)
+• /maintenance remove (id) - Удалить окно обслуживания
+
+🚫 Управление черным списком:
+• /blacklist (символ) (секунды) - Добавить монету в черный список на указанное время
+• /blacklist (символ,символ,...) (секунды) - Добавить несколько монет сразу
+• Пример: /blacklist BTC 3600 (заблокировать BTC на 1 час)
+• /blacklist status (символ) - Проверить, в черном списке ли монета
+
+🎯 Ценовые уровни:
+• /alert (символ) &gt; (цена) - Уведомить, когда цена поднимется до уровня
+• /alert (символ) &lt; (цена) - Уведомить, когда цена опустится до уровня
+• Добавьте repeat в конце, чтобы уровень срабатывал повторно
+• /alert - Показать ваши уровни, /alert remove (id) - удалить уровень
+
+📈 Алерты:
+Алерты отправляются когда:
+- Цена изменяется на указанный процент в течение интервала времени
+- Объем торгов превышает минимальный порог
+- Монета не находится в черном списке
+
+Примеры использования:
+/set time 10
+/set volume 10000
+/set change 3.0
+/blacklist DOGE 1800`
+
+	b.sendMessage(message.Chat.ID, helpMsg)
+}
+
+func (b *Bot) handleTestCommand(message *tgbotapi.Message, args string) {
+	change, volume, ok := parseTestAlertArgs(args)
+	if !ok {
+		b.sendMessage(message.Chat.ID, "Использование: /test [изменение_в_процентах] [объем]\nПример: /test -7.5 250000")
+		return
+	}
+
+	b.sendMessage(message.Chat.ID, "🧪 Отправка тестового алерта...")
+
+	if err := b.SendAlert(testAlertSymbol, change, volume, time.Now()); err != nil {
+		b.sendMessage(message.Chat.ID, "❌ Не удалось отправить тестовый алерт")
+	} else {
+		b.sendMessage(message.Chat.ID, "✅ Тестовый алерт отправлен успешно!")
+	}
+}
+
+func parseTestAlertArgs(args string) (float64, int, bool) {
+	change, volume := testAlertChange, testAlertVolume
+
+	parts := strings.Fields(args)
+	if len(parts) > 2 {
+		return 0, 0, false
+	}
+
+	if len(parts) >= 1 {
+		value, err := strconv.ParseFloat(parts[0], 64)
+		if err != nil || math.IsNaN(value) || math.IsInf(value, 0) {
+			return 0, 0, false
+		}
+		change = value
+	}
+
+	if len(parts) == 2 {
+		value, err := strconv.Atoi(parts[1])
+		if err != nil || value < 0 {
+			return 0, 0, false
+		}
+		volume = value
+	}
+
+	return change, volume, true
+}
+
+func (b *Bot) SendAlert(symbol string, priceChange float64, volume int, timestamp time.Time) error {
+	if b.alreadySent(symbol, timestamp) {
+		log.Infof("Алерт по %s за этот интервал уже был отправлен, пропускаем", symbol)
+		return nil
+	}
+
+	volumeStr := b.formatMoney(volume)
+	display := b.displaySymbol(symbol)
+	detailed := formatAlertMessage(display, priceChange, volume, b.minVolumeFor(priceChange), volumeStr, timestamp, b.cfg.Display.MaxEmojis)
+	compact := formatCompactAlertMessage(display, priceChange, volumeStr)
+
+	return b.broadcast(eventMove, symbol, detailed, compact)
+}
+
+func (b *Bot) SendSustainedAlert(symbol string, priceChange float64, volume int, cycles int, timestamp time.Time) error {
+	priceChangeStr := fmt.Sprintf("%+.2f%%", priceChange)
+	volumeStr := b.formatMoney(volume)
+
+	detailed := fmt.Sprintf("🚀 <b>SUSTAINED</b>\n\n"+
+		"<b>%s</b>\n\n"+
+		"📈 <b>Изменение цены:</b> %s %s\n"+
+		"🔁 <b>Держится:</b> %d интервалов подряд\n"+
+		"💰 <b>Объём торгов:</b> %s\n"+
+		"⏰ <b>Время:</b> %s",
+		html.EscapeString(b.displaySymbol(symbol)), priceChangeStr, getPriceEmojis(priceChange, b.cfg.Display.MaxEmojis), cycles, volumeStr, timestamp.Format("15:04:05"))
+	compact := fmt.Sprintf("🚀 <b>%s</b> %s x%d %s", html.EscapeString(b.displaySymbol(symbol)), priceChangeStr, cycles, volumeStr)
+
+	return b.broadcast(eventSustained, symbol, detailed, compact)
+}
+
+func (b *Bot) SendAccelerationAlert(symbol string, priorChange, recentChange float64, volume int, timestamp time.Time) error {
+	volumeStr := b.formatMoney(volume)
+
+	detailed := fmt.Sprintf("⚡ <b>ACCELERATING</b>\n\n"+
+		"<b>%s</b>\n\n"+
+		"📈 <b>Первая половина интервала:</b> %+.2f%%\n"+
+		"🚀 <b>Вторая половина интервала:</b> %+.2f%% %s\n"+
+		"💰 <b>Объём торгов:</b> %s\n"+
+		"⏰ <b>Время:</b> %s",
+		html.EscapeString(b.displaySymbol(symbol)), priorChange, recentChange, getPriceEmojis(recentChange, b.cfg.Display.MaxEmojis), volumeStr, timestamp.Format("15:04:05"))
+	compact := fmt.Sprintf("⚡ <b>%s</b> %+.2f%% → %+.2f%% %s", html.EscapeString(b.displaySymbol(symbol)), priorChange, recentChange, volumeStr)
+
+	return b.broadcast(eventAcceleration, symbol, detailed, compact)
+}
+
+func (b *Bot) SendCandlesAlert(symbol string, candles int, priceChange float64, timestamp time.Time) error {
+	direction, arrow := "зеленых", "🟢"
+	if priceChange < 0 {
+		direction, arrow = "красных", "🔴"
+	}
+
+	detailed := fmt.Sprintf("🕯 <b>CANDLES</b>\n\n"+
+		"<b>%s</b>\n\n"+
+		"%s <b>Подряд:</b> %d %s свечей\n"+
+		"📈 <b>Изменение цены:</b> %+.2f%% %s\n"+
+		"⏰ <b>Время:</b> %s",
+		html.EscapeString(b.displaySymbol(symbol)), arrow, candles, direction, priceChange, getPriceEmojis(priceChange, b.cfg.Display.MaxEmojis), timestamp.Format("15:04:05"))
+	compact := fmt.Sprintf("🕯 <b>%s</b> %s x%d %+.2f%%", html.EscapeString(b.displaySymbol(symbol)), arrow, candles, priceChange)
+
+	return b.broadcast(eventCandles, symbol, detailed, compact)
+}
+
+func (b *Bot) SendAlertSummary(changes []SymbolChange, timestamp time.Time) error {
+	detailed := fmt.Sprintf("📋 <b>Ещё %d монет выполнили условия алерта</b>\n\n", len(changes))
+	compact := fmt.Sprintf("📋 +%d:", len(changes))
+	for _, change := range changes {
+		symbol := html.EscapeString(b.displaySymbol(change.Symbol))
+		detailed += fmt.Sprintf("• <b>%s</b> %+.2f%%\n", symbol, change.Change)
+		compact += fmt.Sprintf(" %s %+.2f%%", symbol, change.Change)
+	}
+	detailed += fmt.Sprintf("\n⏰ <b>Время:</b> %s", timestamp.Format("15:04:05"))
+
+	return b.broadcast(eventMove, summaryAlertKey, detailed, compact)
+}
+
+func (b *Bot) minVolumeFor(priceChange float64) int {
+	settings, err := b.db.GetSettings()
+	if err != nil {
+		log.Errorf("Failed to get settings: %v", err)
+		return defaultEmojiVolume
+	}
+	return settings.VolumeThreshold(priceChange)
+}
+
+func (b *Bot) displaySymbol(symbol string) string {
+	if b.monitor == nil {
+		return symbol
+	}
+	return b.monitor.FormatSymbol(symbol)
+}
+
+func (b *Bot) tradeLink(symbol string) string {
+	if !b.cfg.Telegram.IncludeLink || b.monitor == nil || symbol == summaryAlertKey {
+		return ""
+	}
+
+	url := b.monitor.TradeURL(symbol)
+	if url == "" {
+		return ""
+	}
+	return fmt.Sprintf(`<a href="%s">Открыть на MEXC</a>`, url)
+}
+
+// broadcast delivers an alert to every user and returns an error only when
+// it reached none of them.
+func (b *Bot) broadcast(event, symbol, detailed, compact string) error {
+	users := b.users()
+
+	link := b.tradeLink(symbol)
+	if link != "" {
+		detailed += "\n🔗 " + link
+		compact += " | " + link
+	}
+
+	log.Infof("Отправка алерта %d пользователям", len(users))
+
+	failed := b.fanOut(users, func(userID int64) error {
+		userSettings, ok := b.deliverable(userID, event, symbol)
+		if !ok {
+			return nil
+		}
+		message := detailed
+		if userSettings != nil && userSettings.Format == "compact" {
+			message = compact
+		}
+
+		msg := tgbotapi.NewMessage(userID, message)
+		msg.ParseMode = "HTML"
+		msg.DisableWebPagePreview = link != ""
+		if symbol != summaryAlertKey {
+			msg.ReplyMarkup = snoozeKeyboard(symbol)
+		}
+
+		if err := b.send(userID, msg); err != nil {
+			log.Errorf("Не удалось отправить алерт пользователю %d: %v", userID, err)
+			return err
+		}
+		log.Infof("Успешно отправлен алерт пользователю %d", userID)
+		return nil
+	})
+
+	if failed > 0 {
+		log.Warnf("Алерт %s не доставлен %d из %d пользователей", symbol, failed, len(users))
+	}
+
+	if len(users) == 0 {
+		log.Warn("Нет пользователей в списке разрешенных. Отправьте /start боту сначала!")
+	} else if failed == len(users) {
+		return fmt.Errorf("алерт %s не доставлен ни одному из %d пользователей", symbol, len(users))
+	}
+	return nil
+}
+
+// deliverable applies the per-user checks every alert goes through: event
+// subscription, trading sessions, mutes and the daily limit. When the user's
+// settings cannot be loaded they are nil and the alert is still delivered.
+func (b *Bot) deliverable(userID int64, event, symbol string) (*database.UserSettings, bool) {
+	userSettings, err := b.db.GetUserSettings(userID)
+	if err != nil {
+		log.Errorf("Не удалось получить настройки пользователя %d: %v", userID, err)
+		return nil, true
+	}
+	if !wantsEvent(userSettings, event) {
+		log.Debugf("Пользователь %d не подписан на алерты %s, алерт пропущен", userID, event)
+		return userSettings, false
+	}
+	if !inTradingSession(userSettings, time.Now()) {
+		log.Debugf("Пользователь %d вне своих торговых сессий, алерт пропущен", userID)
+		return userSettings, false
+	}
+	if b.isMuted(userID, symbol) {
+		return userSettings, false
+	}
+	if !b.withinDailyLimit(userID, userSettings) {
+		return userSettings, false
+	}
+	return userSettings, true
+}
+
+func (b *Bot) fanOut(users []int64, deliver func(userID int64) error) int {
+	concurrency := b.cfg.Telegram.SendConcurrency
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	var (
+		wg     sync.WaitGroup
+		mu     sync.Mutex
+		failed int
+	)
+	sem := make(chan struct{}, concurrency)
+
+	for _, userID := range users {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(userID int64) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			if err := deliver(userID); err != nil {
+				mu.Lock()
+				failed++
+				mu.Unlock()
+			}
+		}(userID)
+	}
+
+	wg.Wait()
+	return failed
+}
+
+func (b *Bot) isAdmin(userID int64) bool {
+	for _, adminID := range b.admins {
+		if adminID == userID {
+			return true
+		}
+	}
+	return false
+}
+
+func (b *Bot) requireAdmin(message *tgbotapi.Message) bool {
+	if b.isAdmin(message.From.ID) {
+		return true
+	}
+	b.sendMessage(message.Chat.ID, "⛔ Команда доступна только администраторам")
+	return false
+}
+
+func (b *Bot) NotifyAdmins(text string) {
+	if len(b.admins) == 0 {
+		log.Debugf("Администраторы не настроены, уведомление пропущено: %s", text)
+		return
+	}
+
+	for _, adminID := range b.admins {
+		b.sendMessage(adminID, text)
+	}
+}
+
+func (b *Bot) SendLevelAlert(chatID int64, symbol string, above bool, level, price float64) error {
+	if _, ok := b.deliverable(chatID, eventLevel, symbol); !ok {
+		return nil
+	}
+
+	direction := "опустилась до"
+	if above {
+		direction = "поднялась до"
+	}
+
+	text := fmt.Sprintf("🎯 <b>ЦЕНОВОЙ УРОВЕНЬ</b>\n\n"+
+		"<b>%s</b>\n\n"+
+		"Цена %s %s\n"+
+		"💵 <b>Текущая цена:</b> %s\n"+
+		"⏰ <b>Время:</b> %s",
+		html.EscapeString(b.displaySymbol(symbol)), direction, formatPrice(level), formatPrice(price), time.Now().Format("15:04:05"))
+
+	link := b.tradeLink(symbol)
+	if link != "" {
+		text += "\n🔗 " + link
+	}
+
+	msg := tgbotapi.NewMessage(chatID, text)
+	msg.ParseMode = "HTML"
+	msg.DisableWebPagePreview = link != ""
+
+	return b.send(chatID, msg)
+}
+
+func (b *Bot) AddUser(userID int64) {
+	b.usersMu.Lock()
+	b.allowedUsers[userID] = true
+	b.usersMu.Unlock()
+
+	if err := b.db.AddUser(userID); err != nil {
+		log.Errorf("Не удалось сохранить пользователя %d: %v", userID, err)
+	}
+	log.Infof("Добавлен пользователь %d в список разрешенных", userID)
+}
+
+func (b *Bot) RemoveUser(userID int64) {
+	b.usersMu.Lock()
+	delete(b.allowedUsers, userID)
+	b.usersMu.Unlock()
+
+	if err := b.db.RemoveUser(userID); err != nil {
+		log.Errorf("Не удалось удалить пользователя %d из базы данных: %v", userID, err)
+	}
+	log.Infof("Удален пользователь %d из списка разрешенных", userID)
+}
+
+func (b *Bot) users() []int64 {
+	b.usersMu.RLock()
+	defer b.usersMu.RUnlock()
+
+	users := make([]int64, 0, len(b.allowedUsers))
+	for userID := range b.allowedUsers {
+		users = append(users, userID)
+	}
+	return users
+}
+
+func (b *Bot) sendMessage(chatID int64, text string) {
+	msg := tgbotapi.NewMessage(chatID, text)
+	msg.ParseMode = "HTML"
+
+	if err := b.send(chatID, msg); err != nil {
+		log.Errorf("Failed to send message to %d: %v", chatID, err)
+	}
+}
+
+func (b *Bot) sendLongMessage(chatID int64, text string) {
+	for _, chunk := range splitMessage(text, maxMessageLength) {
+		b.sendMessage(chatID, chunk)
+	}
+}
+
+func splitMessage(text string, limit int) []string {
+	if utf8.RuneCountInString(text) <= limit {
+		return []string{text}
+	}
+
+	var chunks []string
+	var current strings.Builder
+	currentLen := 0
+
+	flush := func() {
+		if currentLen > 0 {
+			chunks = append(chunks, current.String())
+			current.Reset()
+			currentLen = 0
+		}
+	}
+
+	for _, line := range strings.SplitAfter(text, "\n") {
+		lineLen := utf8.RuneCountInString(line)
+		if currentLen+lineLen > limit {
+			flush()
+		}
+
+		for lineLen > limit {
+			runes := []rune(line)
+			chunks = append(chunks, string(runes[:limit]))
+			line = string(runes[limit:])
+			lineLen -= limit
+		}
+
+		current.WriteString(line)
+		currentLen += lineLen
+	}
+	flush()
+
+	return chunks
+}
+
+func (b *Bot) send(chatID int64, c tgbotapi.Chattable) error {
+	<-b.sendLimiter
+	_, err := b.sender.Send(c)
+	if err == nil {
+		return nil
+	}
+
+	if isChatUnavailable(err) {
+		if b.isAdmin(chatID) {
+			log.Warnf("Чат администратора %d недоступен: %v", chatID, err)
+			return err
+		}
+		log.Warnf("Чат %d недоступен, пользователь удален из рассылки: %v", chatID, err)
+		b.RemoveUser(chatID)
+		return err
+	}
+
+	if !isRetryable(err) {
+		return err
+	}
+
+	go b.retrySend(chatID, c, err)
+	return nil
+}
+
+func (b *Bot) retrySend(chatID int64, c tgbotapi.Chattable, err error) {
+	for attempt := 1; attempt < sendAttempts; attempt++ {
+		delay := sendRetryDelay * time.Duration(attempt)
+		var apiErr *tgbotapi.Error
+		if errors.As(err, &apiErr) && apiErr.RetryAfter > 0 {
+			delay = time.Duration(apiErr.RetryAfter) * time.Second
+		}
+
+		log.Warnf("Ошибка отправки в чат %d (попытка %d/%d): %v, повтор через %s",
+			chatID, attempt, sendAttempts, err, delay)
+
+		select {
+		case <-time.After(delay):
+		case <-b.stopChan:
+			return
+		}
+
+		if _, err := b.sender.GetMe(); err != nil {
+			log.Warnf("Telegram API недоступен: %v", err)
+		}
+
+		<-b.sendLimiter
+		if _, err = b.sender.Send(c); err == nil {
+			return
+		}
+		if !isRetryable(err) {
+			break
+		}
+	}
+
+	log.Errorf("Не удалось отправить сообщение в чат %d: %v", chatID, err)
+}
+
+func isRetryable(err error) bool {
+	var apiErr *tgbotapi.Error
+	if !errors.As(err, &apiErr) {
+		return true
+	}
+	return apiErr.Code == 429 || apiErr.Code >= 500
+}
+
+func isChatUnavailable(err error) bool {
+	var apiErr *tgbotapi.Error
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+
+	switch apiErr.Code {
+	case 403:
+		return true
+	case 400:
+		return strings.Contains(apiErr.Message, "chat not found")
+	}
+	return false
+}
+
+func formatAlertMessage(symbol string, priceChange float64, volume, minVolume int, volumeStr string, timestamp time.Time, maxEmojis int) string {
+	priceChangeStr := fmt.Sprintf("%.2f%%", priceChange)
 	if priceChange > 0 {
 		priceChangeStr = "+" + priceChangeStr
 	}
 
-	volumeStr := formatVolume(volume)
-
-	volumeEmojis := getVolumeEmojis(volume)
-	priceEmojis := getPriceEmojis(priceChange)
+	volumeEmojis := getVolumeEmojis(volume, minVolume, maxEmojis)
+	priceEmojis := getPriceEmojis(priceChange, maxEmojis)
 
 	timeStr := timestamp.Format("15:04:05")
 
@@ -354,7 +1663,20 @@ func formatAlertMessage(symbol string, priceChange float64, volume int, timestam
 		"📈 <b>Изменение цены:</b> %s %s\n"+
 		"💰 <b>Объём торгов:</b> %s %s\n"+
 		"⏰ <b>Время:</b> %s",
-		symbol, priceChangeStr, priceEmojis, volumeStr, volumeEmojis, timeStr)
+		html.EscapeString(symbol), priceChangeStr, priceEmojis, volumeStr, volumeEmojis, timeStr)
+}
+
+func formatCompactAlertMessage(symbol string, priceChange float64, volumeStr string) string {
+	priceChangeStr := fmt.Sprintf("%.2f%%", priceChange)
+	if priceChange > 0 {
+		priceChangeStr = "+" + priceChangeStr
+	}
+
+	return fmt.Sprintf("<b>%s</b> %s %s", html.EscapeString(symbol), priceChangeStr, volumeStr)
+}
+
+func formatPrice(price float64) string {
+	return strconv.FormatFloat(price, 'f', -1, 64)
 }
 
 func formatVolume(volume int) string {
@@ -366,36 +1688,89 @@ func formatVolume(volume int) string {
 	return fmt.Sprintf("%d", volume)
 }
 
-func getVolumeEmojis(volume int) string {
-	if volume < 10000 {
+func getVolumeEmojis(volume, minVolume int, maxEmojis int) string {
+	if minVolume <= 0 {
+		minVolume = defaultEmojiVolume
+	}
+	if volume < minVolume {
 		return ""
-	} else if volume < 50000 {
-		return "👁"
-	} else if volume < 100000 {
-		return "👁🔥"
-	} else if volume < 150000 {
-		return "👁🔥🔥"
-	} else if volume < 200000 {
-		return "👁🔥🔥🔥"
-	} else {
-		fireCount := (volume-200000)/50000 + 3
-		if fireCount > 10 {
-			fireCount = 10
-		}
-		fires := strings.Repeat("🔥", fireCount)
-		return "👁" + fires
 	}
+
+	ratio := volume / minVolume
+	fireCount := 0
+	switch {
+	case ratio >= 10:
+		fireCount = ratio/10 + 2
+	case ratio >= 5:
+		fireCount = 2
+	case ratio >= 2:
+		fireCount = 1
+	}
+	return "👁" + repeatEmoji("🔥", fireCount, maxEmojis)
 }
 
-func getPriceEmojis(priceChange float64) string {
+func getPriceEmojis(priceChange float64, maxEmojis int) string {
 	change := math.Abs(priceChange)
 
 	circleCount := int(change/10) + 1
-	if circleCount > 10 {
-		circleCount = 10
+	return repeatEmoji("🔵", circleCount, maxEmojis)
+}
+
+func repeatEmoji(emoji string, count, maxEmojis int) string {
+	if maxEmojis < 1 {
+		maxEmojis = 1
+	}
+	if count > maxEmojis {
+		return fmt.Sprintf("%s×%d", emoji, count)
 	}
+	return strings.Repeat(emoji, count)
+}
+
+func parseLimit(args string) (int, bool) {
+	args = strings.TrimSpace(args)
+	if args == "" {
+		return 10, true
+	}
+
+	value, err := strconv.Atoi(args)
+	if err != nil || value <= 0 || value > 50 {
+		return 0, false
+	}
+	return value, true
+}
+
+func parseSymbolList(input string) (symbols []string, invalid []string) {
+	seen := make(map[string]bool)
+	fields := strings.FieldsFunc(input, func(r rune) bool {
+		return r == ',' || r == ';' || r == ' ' || r == '\n' || r == '\r' || r == '\t'
+	})
+
+	for _, field := range fields {
+		symbol := strings.ToUpper(field)
+		if seen[symbol] {
+			continue
+		}
+		seen[symbol] = true
+
+		if !isValidSymbol(symbol) {
+			invalid = append(invalid, field)
+			continue
+		}
+		symbols = append(symbols, symbol)
+	}
+	return symbols, invalid
+}
 
-	return strings.Repeat("🔵", circleCount)
+func isValidSymbol(symbol string) bool {
+	if symbol == "" {
+		return false
+	}
+	for _, r := range symbol {
+		if (r < 'A' || r > 'Z') && (r < '0' || r > '9') {
+			return false
+		}
+	}
+	return true
 }
 
 func formatDuration(d time.Duration) string {
@@ -0,0 +1,567 @@
+package telegram
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"mexc-monitor/internal/config"
+	"mexc-monitor/internal/database"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+const (
+	testUserID  int64 = 100
+	testAdminID int64 = 200
+)
+
+type sentMessage struct {
+	chatID int64
+	text   string
+}
+
+type sentDocument struct {
+	chatID int64
+	data   []byte
+}
+
+type fakeSender struct {
+	mu        sync.Mutex
+	messages  []sentMessage
+	documents []sentDocument
+	sendErr   error
+	attempts  int
+}
+
+func (f *fakeSender) Send(c tgbotapi.Chattable) (tgbotapi.Message, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.attempts++
+	if f.sendErr != nil {
+		return tgbotapi.Message{}, f.sendErr
+	}
+
+	if msg, ok := c.(tgbotapi.MessageConfig); ok {
+		f.messages = append(f.messages, sentMessage{chatID: msg.ChatID, text: msg.Text})
+	}
+	if doc, ok := c.(tgbotapi.DocumentConfig); ok {
+		f.documents = append(f.documents, sentDocument{chatID: doc.ChatID, data: doc.File.(tgbotapi.FileBytes).Bytes})
+	}
+	return tgbotapi.Message{}, nil
+}
+
+func (f *fakeSender) Request(c tgbotapi.Chattable) (*tgbotapi.APIResponse, error) {
+	return &tgbotapi.APIResponse{Ok: true}, nil
+}
+
+func (f *fakeSender) GetMe() (tgbotapi.User, error) {
+	return tgbotapi.User{UserName: "test_bot"}, nil
+}
+
+func (f *fakeSender) GetFileDirectURL(fileID string) (string, error) {
+	return "", nil
+}
+
+func (f *fakeSender) repliesTo(chatID int64) []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	var replies []string
+	for _, msg := range f.messages {
+		if msg.chatID == chatID {
+			replies = append(replies, msg.text)
+		}
+	}
+	return replies
+}
+
+func newTestBot(t *testing.T) (*Bot, *fakeSender, database.Store) {
+	t.Helper()
+
+	db, err := database.New(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("failed to open test database: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	limiter := make(chan time.Time)
+	close(limiter)
+
+	fake := &fakeSender{}
+	bot := &Bot{
+		sender:       fake,
+		cfg:          &config.Config{Telegram: config.TelegramConfig{Admins: []int64{testAdminID}}},
+		db:           db,
+		stopChan:     make(chan struct{}),
+		allowedUsers: make(map[int64]bool),
+		admins:       []int64{testAdminID},
+		sendLimiter:  limiter,
+		commandTimes: make(map[int64][]time.Time),
+	}
+	return bot, fake, db
+}
+
+func commandMessage(userID int64, text string) *tgbotapi.Message {
+	command := strings.Fields(text)[0]
+	return &tgbotapi.Message{
+		Text: text,
+		Chat: &tgbotapi.Chat{ID: userID},
+		From: &tgbotapi.User{ID: userID, UserName: "tester"},
+		Entities: []tgbotapi.MessageEntity{
+			{Type: "bot_command", Offset: 0, Length: len(command)},
+		},
+	}
+}
+
+type commandCase struct {
+	name  string
+	user  int64
+	text  string
+	reply string
+	check func(t *testing.T, db database.Store)
+}
+
+func runCommandCases(t *testing.T, cases []commandCase) {
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			bot, fake, db := newTestBot(t)
+
+			user := tc.user
+			if user == 0 {
+				user = testUserID
+			}
+			bot.handleCommand(commandMessage(user, tc.text))
+
+			replies := fake.repliesTo(user)
+			if len(replies) == 0 {
+				t.Fatalf("%q: no reply sent", tc.text)
+			}
+			if !strings.Contains(replies[0], tc.reply) {
+				t.Errorf("%q: reply %q does not contain %q", tc.text, replies[0], tc.reply)
+			}
+			if tc.check != nil {
+				tc.check(t, db)
+			}
+		})
+	}
+}
+
+func globalSettings(t *testing.T, db database.Store) *database.Settings {
+	t.Helper()
+
+	settings, err := db.GetSettings()
+	if err != nil {
+		t.Fatalf("failed to get settings: %v", err)
+	}
+	return settings
+}
+
+func userSettings(t *testing.T, db database.Store, userID int64) *database.UserSettings {
+	t.Helper()
+
+	settings, err := db.GetUserSettings(userID)
+	if err != nil {
+		t.Fatalf("failed to get user settings: %v", err)
+	}
+	return settings
+}
+
+func TestSetCommand(t *testing.T) {
+	runCommandCases(t, []commandCase{
+		{name: "no arguments", text: "/set", reply: "Использование: /set"},
+		{name: "missing value", text: "/set time", reply: "Использование: /set"},
+		{name: "unknown parameter", text: "/set speed 10", reply: "Неизвестный параметр"},
+		{name: "time not a number", text: "/set time abc", reply: "Неверное значение времени"},
+		{name: "time not positive", text: "/set time 0", reply: "Неверное значение времени"},
+		{
+			name: "time", text: "/set time 10", reply: "Интервал времени установлен на 10 секунд",
+			check: func(t *testing.T, db database.Store) {
+				if got := globalSettings(t, db).TimeInterval; got != 10 {
+					t.Errorf("TimeInterval = %d, want 10", got)
+				}
+			},
+		},
+		{name: "volume negative", text: "/set volume -1", reply: "Неверное значение объема"},
+		{
+			name: "volume", text: "/set volume 10000", reply: "Минимальный объем установлен на $10000",
+			check: func(t *testing.T, db database.Store) {
+				if got := globalSettings(t, db).MinVolume; got != 10000 {
+					t.Errorf("MinVolume = %d, want 10000", got)
+				}
+			},
+		},
+		{
+			name: "volume down", text: "/set volume_down 2000", reply: "Минимальный объем для падения установлен на $2000",
+			check: func(t *testing.T, db database.Store) {
+				settings := globalSettings(t, db)
+				if settings.MinVolumeDown != 2000 || settings.MinVolumeUp != 0 {
+					t.Errorf("MinVolumeUp/Down = %d/%d, want 0/2000", settings.MinVolumeUp, settings.MinVolumeDown)
+				}
+			},
+		},
+		{name: "volume up reset", text: "/set volume_up 0", reply: "сброшен, используется общий $5000"},
+		{name: "change not a number", text: "/set change fast", reply: "Неверное значение изменения"},
+		{
+			name: "change", text: "/set change 3.5", reply: "Порог изменения цены установлен на 3.50%",
+			check: func(t *testing.T, db database.Store) {
+				if got := globalSettings(t, db).PriceChange; got != 3.5 {
+					t.Errorf("PriceChange = %.2f, want 3.5", got)
+				}
+			},
+		},
+		{
+			name: "retention requires admin", text: "/set retention 30", reply: "только администраторам",
+			check: func(t *testing.T, db database.Store) {
+				if got := globalSettings(t, db).Retention; got != 10 {
+					t.Errorf("Retention = %d, want unchanged 10", got)
+				}
+			},
+		},
+		{
+			name: "retention as admin", user: testAdminID, text: "/set retention 30", reply: "Хранение истории цен установлено на 30 минут",
+			check: func(t *testing.T, db database.Store) {
+				if got := globalSettings(t, db).Retention; got != 30 {
+					t.Errorf("Retention = %d, want 30", got)
+				}
+			},
+		},
+		{name: "format unknown", text: "/set format fancy", reply: "Неверный формат"},
+		{
+			name: "format", text: "/set format COMPACT", reply: "Формат алертов установлен: compact",
+			check: func(t *testing.T, db database.Store) {
+				if got := userSettings(t, db, testUserID).Format; got != "compact" {
+					t.Errorf("Format = %q, want compact", got)
+				}
+			},
+		},
+		{name: "maxalerts negative", text: "/set maxalerts -3", reply: "Неверное значение"},
+		{name: "maxalerts off", text: "/set maxalerts 0", reply: "Дневной лимит алертов отключен"},
+		{name: "timezone unknown", text: "/set timezone Mars/Base", reply: "Неизвестный часовой пояс"},
+		{name: "sessions unknown", text: "/set sessions mars", reply: "Неверные сессии"},
+		{name: "sessions error is escaped", text: "/set sessions <x>", reply: "неизвестная сессия &#34;&lt;x&gt;&#34;"},
+		{name: "override usage", text: "/set override DOGEUSDT 600", reply: "Использование: /set override"},
+		{name: "override bad symbol", text: "/set override DOGE-USDT cooldown 600", reply: "Неверный символ"},
+		{name: "override bad cooldown", text: "/set override DOGEUSDT cooldown -1", reply: "Неверный кулдаун"},
+		{
+			name: "override cooldown", text: "/set override dogeusdt cooldown 600", reply: "Кулдаун для DOGEUSDT установлен на",
+			check: func(t *testing.T, db database.Store) {
+				cooldowns, err := db.GetSymbolCooldowns()
+				if err != nil || cooldowns["DOGEUSDT"] != 600 {
+					t.Errorf("GetSymbolCooldowns() = %v, %v; want DOGEUSDT: 600", cooldowns, err)
+				}
+			},
+		},
+		{
+			name: "sessions", text: "/set sessions eu,us", reply: "Алерты будут приходить только в сессии (UTC): eu,us",
+			check: func(t *testing.T, db database.Store) {
+				if got := userSettings(t, db, testUserID).Sessions; got != "eu,us" {
+					t.Errorf("Sessions = %q, want eu,us", got)
+				}
+			},
+		},
+	})
+}
+
+func TestBlacklistCommand(t *testing.T) {
+	runCommandCases(t, []commandCase{
+		{name: "empty list", text: "/blacklist", reply: "Черный список пуст"},
+		{name: "missing duration", text: "/blacklist BTCUSDT", reply: "Использование: /blacklist"},
+		{name: "duration not a number", text: "/blacklist BTCUSDT soon", reply: "Неверная длительность"},
+		{name: "duration not positive", text: "/blacklist BTCUSDT -60", reply: "Неверная длительность"},
+		{name: "no valid symbols", text: "/blacklist BTC-USDT 60", reply: "Не указано ни одного корректного символа"},
+		{
+			name: "single symbol", text: "/blacklist btcusdt 3600", reply: "Добавлено BTCUSDT в черный список",
+			check: func(t *testing.T, db database.Store) {
+				if blacklisted, err := db.IsBlacklisted("BTCUSDT"); err != nil || !blacklisted {
+					t.Errorf("IsBlacklisted(BTCUSDT) = %t, %v; want true", blacklisted, err)
+				}
+			},
+		},
+		{
+			name: "several symbols with invalid", text: "/blacklist ETHUSDT,DOGEUSDT,BAD-ONE 60", reply: "❌ BAD-ONE (неверный символ)",
+			check: func(t *testing.T, db database.Store) {
+				for _, symbol := range []string{"ETHUSDT", "DOGEUSDT"} {
+					if blacklisted, err := db.IsBlacklisted(symbol); err != nil || !blacklisted {
+						t.Errorf("IsBlacklisted(%s) = %t, %v; want true", symbol, blacklisted, err)
+					}
+				}
+			},
+		},
+	})
+}
+
+func TestApplyCommandAuditsChanges(t *testing.T) {
+	const otherAdminID int64 = 201
+
+	bot, fake, db := newTestBot(t)
+	bot.admins = append(bot.admins, otherAdminID)
+
+	settings := globalSettings(t, db)
+	settings.PriceChange = 4.5
+	settings.MinVolume = 20000
+	code, err := encodeSettings(settings)
+	if err != nil {
+		t.Fatalf("encodeSettings() error = %v", err)
+	}
+
+	bot.handleCommand(commandMessage(testAdminID, "/apply "+code))
+
+	notices := strings.Join(fake.repliesTo(otherAdminID), "\n")
+	for _, key := range []string{"<b>change</b>: 2 → 4.5", "<b>volume</b>: 5000 → 20000"} {
+		if !strings.Contains(notices, key) {
+			t.Errorf("other admin not notified about %q, got %q", key, notices)
+		}
+	}
+	if strings.Contains(notices, "<b>time</b>") {
+		t.Errorf("unchanged setting reported: %q", notices)
+	}
+}
+
+func TestSubscribeCommand(t *testing.T) {
+	runCommandCases(t, []commandCase{
+		{name: "show defaults", text: "/subscribe", reply: "✅ <code>move</code>"},
+		{name: "unknown type", text: "/subscribe moon", reply: "неизвестный тип алертов"},
+		{name: "unknown type is escaped", text: "/subscribe <b>", reply: "неизвестный тип алертов &#34;&lt;b&gt;&#34;"},
+		{
+			name: "selected types", text: "/subscribe move, level", reply: "Вы подписаны на алерты: move,level",
+			check: func(t *testing.T, db database.Store) {
+				settings := userSettings(t, db, testUserID)
+				if !wantsEvent(settings, eventLevel) || wantsEvent(settings, eventCandles) {
+					t.Errorf("Events = %q, want move and level only", settings.Events)
+				}
+			},
+		},
+		{name: "all types", text: "/subscribe all", reply: "Вы подписаны на все типы алертов"},
+	})
+}
+
+func TestUnknownCommand(t *testing.T) {
+	runCommandCases(t, []commandCase{
+		{name: "unknown", text: "/moon", reply: "Неизвестная команда"},
+		{name: "admin only", text: "/flush", reply: "только администраторам"},
+	})
+}
+
+func TestUsersPersist(t *testing.T) {
+	bot, _, db := newTestBot(t)
+
+	bot.handleCommand(commandMessage(testUserID, "/start"))
+	bot.AddUser(testAdminID)
+	bot.RemoveUser(testAdminID)
+
+	restarted, _, _ := newTestBot(t)
+	restarted.db = db
+	if _, err := restarted.loadUsers(); err != nil {
+		t.Fatalf("loadUsers() error = %v", err)
+	}
+
+	users := restarted.users()
+	if len(users) != 1 || users[0] != testUserID {
+		t.Errorf("users after restart = %v, want [%d]", users, testUserID)
+	}
+}
+
+func TestReloadUsersCommand(t *testing.T) {
+	bot, fake, db := newTestBot(t)
+
+	bot.AddUser(testUserID)
+	if err := db.RemoveUser(testUserID); err != nil {
+		t.Fatalf("RemoveUser() error = %v", err)
+	}
+	for _, chatID := range []int64{301, 302} {
+		if err := db.AddUser(chatID); err != nil {
+			t.Fatalf("AddUser() error = %v", err)
+		}
+	}
+
+	bot.handleCommand(commandMessage(testUserID, "/reloadusers"))
+	if len(bot.users()) != 1 {
+		t.Errorf("non-admin reloaded users: %v", bot.users())
+	}
+
+	bot.handleCommand(commandMessage(testAdminID, "/reloadusers"))
+	replies := fake.repliesTo(testAdminID)
+	if len(replies) == 0 || !strings.Contains(replies[0], "перечитан из базы данных: 2 (было 1)") {
+		t.Errorf("reply = %q, want count 2 (было 1)", replies)
+	}
+	if users := bot.users(); len(users) != 2 || bot.allowedUsers[testUserID] {
+		t.Errorf("users after reload = %v, want [301 302]", users)
+	}
+}
+
+func TestSendErrors(t *testing.T) {
+	tests := []struct {
+		name       string
+		chatID     int64
+		code       int
+		wantErr    bool
+		subscribed bool
+	}{
+		{name: "bad request is not retried", chatID: testUserID, code: 400, wantErr: true, subscribed: true},
+		{name: "rate limit is retried in background", chatID: testUserID, code: 429, subscribed: true},
+		{name: "blocked user is unsubscribed", chatID: testUserID, code: 403, wantErr: true},
+		{name: "blocked admin stays subscribed", chatID: testAdminID, code: 403, wantErr: true, subscribed: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bot, fake, db := newTestBot(t)
+			defer close(bot.stopChan)
+
+			bot.AddUser(tt.chatID)
+			fake.sendErr = &tgbotapi.Error{Code: tt.code, Message: "test error"}
+
+			err := bot.send(tt.chatID, tgbotapi.NewMessage(tt.chatID, "test"))
+			if (err != nil) != tt.wantErr {
+				t.Errorf("send() error = %v, wantErr %t", err, tt.wantErr)
+			}
+			if fake.attempts != 1 {
+				t.Errorf("send() made %d attempts before returning, want 1", fake.attempts)
+			}
+
+			users, err := db.GetUsers()
+			if err != nil {
+				t.Fatalf("GetUsers() error = %v", err)
+			}
+			if subscribed := len(users) == 1; subscribed != tt.subscribed {
+				t.Errorf("subscribed = %t, want %t", subscribed, tt.subscribed)
+			}
+		})
+	}
+}
+
+func TestSendAlertReportsUndelivered(t *testing.T) {
+	bot, fake, _ := newTestBot(t)
+	bot.AddUser(testUserID)
+
+	fake.sendErr = &tgbotapi.Error{Code: 400, Message: "test error"}
+	if err := bot.SendAlert("BTCUSDT", 5, 10000, time.Now()); err == nil {
+		t.Errorf("SendAlert() delivered to nobody returned no error")
+	}
+
+	fake.sendErr = nil
+	if err := bot.SendAlert("ETHUSDT", 5, 10000, time.Now()); err != nil {
+		t.Errorf("SendAlert() error = %v", err)
+	}
+}
+
+func TestLevelAlertRespectsMute(t *testing.T) {
+	bot, fake, db := newTestBot(t)
+
+	if err := db.MuteSymbol(testUserID, "BTCUSDT", time.Hour); err != nil {
+		t.Fatalf("MuteSymbol() error = %v", err)
+	}
+	if err := bot.SendLevelAlert(testUserID, "BTCUSDT", true, 100, 101); err != nil {
+		t.Fatalf("SendLevelAlert() error = %v", err)
+	}
+	if replies := fake.repliesTo(testUserID); len(replies) != 0 {
+		t.Errorf("muted level alert delivered: %v", replies)
+	}
+
+	if err := bot.SendLevelAlert(testUserID, "ETHUSDT", true, 100, 101); err != nil {
+		t.Fatalf("SendLevelAlert() error = %v", err)
+	}
+	if replies := fake.repliesTo(testUserID); len(replies) != 1 {
+		t.Errorf("level alert for another symbol: %d messages, want 1", len(replies))
+	}
+}
+
+func TestBackupCommand(t *testing.T) {
+	bot, fake, db := newTestBot(t)
+
+	if err := db.AddToBlacklist("SCAMUSDT", time.Hour); err != nil {
+		t.Fatalf("failed to add to blacklist: %v", err)
+	}
+
+	bot.handleCommand(commandMessage(testUserID, "/backup"))
+	if len(fake.documents) != 0 {
+		t.Fatalf("backup sent to non-admin")
+	}
+
+	bot.handleCommand(commandMessage(testAdminID, "/backup"))
+	if len(fake.documents) != 1 || fake.documents[0].chatID != testAdminID {
+		t.Fatalf("documents = %d, want one backup for admin", len(fake.documents))
+	}
+
+	path := filepath.Join(t.TempDir(), "restored.db")
+	if err := os.WriteFile(path, fake.documents[0].data, 0o600); err != nil {
+		t.Fatalf("failed to write backup: %v", err)
+	}
+	restored, err := database.New(path)
+	if err != nil {
+		t.Fatalf("failed to open backup: %v", err)
+	}
+	defer restored.Close()
+
+	if blacklisted, err := restored.IsBlacklisted("SCAMUSDT"); err != nil || !blacklisted {
+		t.Errorf("restored IsBlacklisted(SCAMUSDT) = %t, %v; want true", blacklisted, err)
+	}
+}
+
+func TestParseSymbolList(t *testing.T) {
+	tests := []struct {
+		input   string
+		symbols []string
+		invalid []string
+	}{
+		{input: "", symbols: nil, invalid: nil},
+		{input: "btcusdt", symbols: []string{"BTCUSDT"}},
+		{input: "BTCUSDT, ethusdt;DOGEUSDT\nBTCUSDT", symbols: []string{"BTCUSDT", "ETHUSDT", "DOGEUSDT"}},
+		{input: "BTCUSDT BTC/USDT", symbols: []string{"BTCUSDT"}, invalid: []string{"BTC/USDT"}},
+	}
+
+	for _, tt := range tests {
+		symbols, invalid := parseSymbolList(tt.input)
+		if strings.Join(symbols, ",") != strings.Join(tt.symbols, ",") {
+			t.Errorf("parseSymbolList(%q) symbols = %v, want %v", tt.input, symbols, tt.symbols)
+		}
+		if strings.Join(invalid, ",") != strings.Join(tt.invalid, ",") {
+			t.Errorf("parseSymbolList(%q) invalid = %v, want %v", tt.input, invalid, tt.invalid)
+		}
+	}
+}
+
+func TestParseSessions(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    int
+		wantErr bool
+	}{
+		{input: "eu", want: 1},
+		{input: "asia, us", want: 2},
+		{input: "08:00-12:00,22:00-02:00", want: 2},
+		{input: "moon", wantErr: true},
+		{input: "08:00-25:00", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		sessions, err := parseSessions(tt.input)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("parseSessions(%q) error = %v, wantErr %t", tt.input, err, tt.wantErr)
+			continue
+		}
+		if !tt.wantErr && len(sessions) != tt.want {
+			t.Errorf("parseSessions(%q) = %d sessions, want %d", tt.input, len(sessions), tt.want)
+		}
+	}
+}
+
+func TestChartSeriesAveragesFullHistory(t *testing.T) {
+	series := make([]float64, 400)
+	for i := range series {
+		series[i] = float64(i % 10)
+	}
+
+	prices, averages := chartSeries(series, 10)
+	if len(prices) != chartWidth || len(averages) != chartWidth {
+		t.Fatalf("chartSeries() = %d prices, %d averages; want %d each", len(prices), len(averages), chartWidth)
+	}
+	if last := averages[len(averages)-1]; last != 4.5 {
+		t.Errorf("MA10 of the last history points = %v, want 4.5", last)
+	}
+}
@@ -0,0 +1,65 @@
+package telegram
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	maxCalmMultiplier = 10
+	maxCalmMinutes    = 24 * 60
+)
+
+func (b *Bot) handleCalmCommand(message *tgbotapi.Message, args string) {
+	if !b.requireAdmin(message) {
+		return
+	}
+
+	if b.monitor == nil {
+		b.sendMessage(message.Chat.ID, "Монитор еще не запущен")
+		return
+	}
+
+	parts := strings.Fields(args)
+	switch {
+	case len(parts) == 0:
+		multiplier, until := b.monitor.CalmState()
+		if multiplier == 0 {
+			b.sendMessage(message.Chat.ID, "Пороги не изменены. Использование: /calm &lt;множитель&gt; &lt;минуты&gt;")
+			return
+		}
+		b.sendMessage(message.Chat.ID, fmt.Sprintf("🧘 Пороги увеличены в %.2g раза еще %s",
+			multiplier, time.Until(until).Round(time.Second)))
+		return
+	case len(parts) == 1 && strings.EqualFold(parts[0], "off"):
+		b.monitor.Calm(0, 0)
+		log.Infof("Пользователь %d отменил временное повышение порогов", message.From.ID)
+		b.sendMessage(message.Chat.ID, "🔔 Пороги восстановлены")
+		return
+	case len(parts) != 2:
+		b.sendMessage(message.Chat.ID, "Использование: /calm &lt;множитель&gt; &lt;минуты&gt;\nПример: /calm 2 30\n/calm off - отменить досрочно")
+		return
+	}
+
+	multiplier, err := strconv.ParseFloat(parts[0], 64)
+	if err != nil || multiplier <= 1 || multiplier > maxCalmMultiplier {
+		b.sendMessage(message.Chat.ID, fmt.Sprintf("Неверный множитель. Должен быть больше 1 и не больше %d.", maxCalmMultiplier))
+		return
+	}
+
+	minutes, err := strconv.Atoi(parts[1])
+	if err != nil || minutes <= 0 || minutes > maxCalmMinutes {
+		b.sendMessage(message.Chat.ID, fmt.Sprintf("Неверная длительность. Должно быть от 1 до %d минут.", maxCalmMinutes))
+		return
+	}
+
+	until := b.monitor.Calm(multiplier, time.Duration(minutes)*time.Minute)
+	log.Infof("Пользователь %d увеличил пороги в %.2f раза на %d минут", message.From.ID, multiplier, minutes)
+	b.sendMessage(message.Chat.ID, fmt.Sprintf("🧘 Пороги изменения цены и объема увеличены в %.2g раза до %s UTC",
+		multiplier, until.UTC().Format("15:04")))
+}
@@ -0,0 +1,142 @@
+package telegram
+
+import (
+	"fmt"
+	"html"
+	"math"
+	"strconv"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+const (
+	chartWidth      = 40
+	defaultMAWindow = 5
+)
+
+var sparkBlocks = []rune("▁▂▃▄▅▆▇█")
+
+func (b *Bot) handleChartCommand(message *tgbotapi.Message, args string) {
+	if b.monitor == nil {
+		b.sendMessage(message.Chat.ID, "Монитор еще не запущен")
+		return
+	}
+
+	parts := strings.Fields(args)
+	if len(parts) < 1 || len(parts) > 2 || !isValidSymbol(strings.ToUpper(parts[0])) {
+		b.sendMessage(message.Chat.ID, "Использование: /chart &lt;символ&gt; [ma|maN]\nПример: /chart BTCUSDT ma10")
+		return
+	}
+	symbol, ok := b.resolveSymbol(message.Chat.ID, parts[0])
+	if !ok {
+		return
+	}
+
+	maWindow := 0
+	if len(parts) == 2 {
+		window, ok := parseMAWindow(parts[1])
+		if !ok {
+			b.sendMessage(message.Chat.ID, "Неверное окно скользящей средней. Используйте ma или ma5 ... ma50")
+			return
+		}
+		maWindow = window
+	}
+
+	series := b.monitor.PriceSeries(symbol)
+	if len(series) < 2 {
+		b.sendMessage(message.Chat.ID, fmt.Sprintf("Недостаточно данных о цене для %s", symbol))
+		return
+	}
+
+	prices, averages := chartSeries(series, maWindow)
+	low, high := bounds(series)
+
+	var response strings.Builder
+	response.WriteString(fmt.Sprintf("📉 <b>%s</b>\n\n", html.EscapeString(symbol)))
+	response.WriteString(fmt.Sprintf("<code>%s</code> цена\n", sparkline(prices, low, high)))
+	if maWindow > 0 {
+		response.WriteString(fmt.Sprintf("<code>%s</code> MA%d\n", sparkline(averages, low, high), maWindow))
+	}
+	response.WriteString(fmt.Sprintf("\nМин: %s\nМакс: %s\nПоследняя: %s",
+		formatPrice(low), formatPrice(high), formatPrice(prices[len(prices)-1])))
+
+	b.sendMessage(message.Chat.ID, response.String())
+}
+
+func parseMAWindow(arg string) (int, bool) {
+	arg = strings.ToLower(arg)
+	if !strings.HasPrefix(arg, "ma") {
+		return 0, false
+	}
+	if arg == "ma" {
+		return defaultMAWindow, true
+	}
+
+	window, err := strconv.Atoi(strings.TrimPrefix(arg, "ma"))
+	if err != nil || window < 2 || window > 50 {
+		return 0, false
+	}
+	return window, true
+}
+
+// chartSeries downsamples the price history to the chart width. The moving
+// average is taken over the full history first, so its window is in price
+// points rather than chart columns.
+func chartSeries(series []float64, maWindow int) ([]float64, []float64) {
+	prices := downsample(series, chartWidth)
+	if maWindow <= 0 {
+		return prices, nil
+	}
+	return prices, downsample(movingAverage(series, maWindow), chartWidth)
+}
+
+func downsample(values []float64, width int) []float64 {
+	if len(values) <= width {
+		return values
+	}
+
+	sampled := make([]float64, width)
+	for i := range sampled {
+		sampled[i] = values[i*(len(values)-1)/(width-1)]
+	}
+	return sampled
+}
+
+func movingAverage(values []float64, window int) []float64 {
+	averages := make([]float64, len(values))
+	sum := 0.0
+	for i, value := range values {
+		sum += value
+		if i >= window {
+			sum -= values[i-window]
+		}
+		count := i + 1
+		if count > window {
+			count = window
+		}
+		averages[i] = sum / float64(count)
+	}
+	return averages
+}
+
+func bounds(values []float64) (float64, float64) {
+	low, high := math.Inf(1), math.Inf(-1)
+	for _, value := range values {
+		low = math.Min(low, value)
+		high = math.Max(high, value)
+	}
+	return low, high
+}
+
+func sparkline(values []float64, low, high float64) string {
+	var line strings.Builder
+	for _, value := range values {
+		level := 0
+		if high > low {
+			level = int((value - low) / (high - low) * float64(len(sparkBlocks)-1))
+		}
+		line.WriteRune(sparkBlocks[level])
+	}
+	return line.String()
+}
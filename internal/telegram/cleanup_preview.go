@@ -0,0 +1,32 @@
+package telegram
+
+import (
+	"fmt"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+func (b *Bot) handleCleanupPreviewCommand(message *tgbotapi.Message) {
+	if !b.requireAdmin(message) {
+		return
+	}
+
+	if b.monitor == nil {
+		b.sendMessage(message.Chat.ID, "Монитор еще не запущен")
+		return
+	}
+
+	preview := b.monitor.CleanupPreview()
+
+	text := fmt.Sprintf(`🧹 <b>Предпросмотр очистки</b>
+
+Хранение истории: %s
+
+Точек истории цен: %d, будет удалено: %d, останется: %d
+Записей объема: %d, будет удалено: %d, останется: %d`,
+		preview.Retention,
+		preview.PricePoints, preview.ExpiredPricePoints, preview.PricePoints-preview.ExpiredPricePoints,
+		preview.VolumeEntries, preview.ExpiredVolumeEntries, preview.VolumeEntries-preview.ExpiredVolumeEntries)
+
+	b.sendMessage(message.Chat.ID, text)
+}
@@ -0,0 +1,35 @@
+package telegram
+
+import "strings"
+
+var currencySigns = map[string]string{
+	"USD": "$",
+	"EUR": "€",
+	"GBP": "£",
+	"RUB": "₽",
+	"UAH": "₴",
+	"KZT": "₸",
+	"TRY": "₺",
+	"JPY": "¥",
+	"CNY": "¥",
+	"INR": "₹",
+}
+
+func (b *Bot) formatMoney(usd int) string {
+	display := b.cfg.Display
+
+	amount := usd
+	if display.Rate > 0 {
+		amount = int(float64(usd) * display.Rate)
+	}
+
+	currency := strings.ToUpper(display.Currency)
+	if currency == "" {
+		currency = "USD"
+	}
+
+	if sign, ok := currencySigns[currency]; ok {
+		return sign + formatVolume(amount)
+	}
+	return formatVolume(amount) + " " + currency
+}
@@ -0,0 +1,93 @@
+package telegram
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"mexc-monitor/internal/database"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	log "github.com/sirupsen/logrus"
+)
+
+func (b *Bot) handleSetMaxAlerts(message *tgbotapi.Message, valueStr string) {
+	value, err := strconv.Atoi(valueStr)
+	if err != nil || value < 0 {
+		b.sendMessage(message.Chat.ID, "Неверное значение. Должно быть целым числом не меньше 0 (0 - без ограничения).")
+		return
+	}
+
+	ok := b.updateUserSettings(message, func(settings *database.UserSettings) {
+		settings.MaxAlertsPerDay = value
+	})
+	if !ok {
+		return
+	}
+
+	if value == 0 {
+		b.sendMessage(message.Chat.ID, "Дневной лимит алертов отключен")
+		return
+	}
+	b.sendMessage(message.Chat.ID, fmt.Sprintf("Дневной лимит алертов установлен: %d", value))
+}
+
+func (b *Bot) handleSetTimezone(message *tgbotapi.Message, valueStr string) {
+	if _, err := time.LoadLocation(valueStr); err != nil {
+		b.sendMessage(message.Chat.ID, "Неизвестный часовой пояс. Пример: Europe/Moscow, UTC")
+		return
+	}
+
+	ok := b.updateUserSettings(message, func(settings *database.UserSettings) {
+		settings.Timezone = valueStr
+	})
+	if !ok {
+		return
+	}
+
+	b.sendMessage(message.Chat.ID, fmt.Sprintf("Часовой пояс установлен: %s", valueStr))
+}
+
+func (b *Bot) updateUserSettings(message *tgbotapi.Message, apply func(settings *database.UserSettings)) bool {
+	userSettings, err := b.db.GetUserSettings(message.Chat.ID)
+	if err != nil {
+		log.Errorf("Failed to get user settings: %v", err)
+		b.sendMessage(message.Chat.ID, "Ошибка получения текущих настроек")
+		return false
+	}
+
+	apply(userSettings)
+	if err := b.db.UpdateUserSettings(message.Chat.ID, userSettings); err != nil {
+		log.Errorf("Failed to update user settings: %v", err)
+		b.sendMessage(message.Chat.ID, "Ошибка сохранения настроек")
+		return false
+	}
+	return true
+}
+
+func userLocation(settings *database.UserSettings) *time.Location {
+	if loc, err := time.LoadLocation(settings.Timezone); err == nil {
+		return loc
+	}
+	return time.UTC
+}
+
+func (b *Bot) withinDailyLimit(userID int64, settings *database.UserSettings) bool {
+	if settings.MaxAlertsPerDay <= 0 {
+		return true
+	}
+
+	day := time.Now().In(userLocation(settings)).Format("2006-01-02")
+	count, err := b.db.IncrementAlertCount(userID, day)
+	if err != nil {
+		log.Errorf("Не удалось обновить счетчик алертов пользователя %d: %v", userID, err)
+		return true
+	}
+
+	if count == settings.MaxAlertsPerDay+1 {
+		log.Infof("Пользователь %d достиг дневного лимита алертов (%d)", userID, settings.MaxAlertsPerDay)
+		b.sendMessage(userID, fmt.Sprintf("🔕 Достигнут дневной лимит алертов (%d). Алерты возобновятся завтра.",
+			settings.MaxAlertsPerDay))
+	}
+	return count <= settings.MaxAlertsPerDay
+}
@@ -0,0 +1,33 @@
+package telegram
+
+import (
+	"fmt"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const defaultDedupeInterval = 5 * time.Second
+
+func (b *Bot) alreadySent(symbol string, timestamp time.Time) bool {
+	if symbol == testAlertSymbol {
+		return false
+	}
+
+	interval := defaultDedupeInterval
+	if settings, err := b.db.GetSettings(); err != nil {
+		log.Errorf("Failed to get settings: %v", err)
+	} else if settings.TimeInterval > 0 {
+		interval = time.Duration(settings.TimeInterval) * time.Second
+	}
+
+	bucket := timestamp.Truncate(interval)
+	key := fmt.Sprintf("%s:%d", symbol, bucket.Unix())
+
+	marked, err := b.db.MarkAlertSent(key, bucket.Add(2*interval))
+	if err != nil {
+		log.Errorf("Не удалось сохранить ключ алерта %s: %v", key, err)
+		return false
+	}
+	return !marked
+}
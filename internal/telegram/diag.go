@@ -0,0 +1,58 @@
+package telegram
+
+import (
+	"fmt"
+	"html"
+	"runtime"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+func (b *Bot) handleDiagCommand(message *tgbotapi.Message) {
+	if !b.requireAdmin(message) {
+		return
+	}
+
+	if b.monitor == nil {
+		b.sendMessage(message.Chat.ID, "Монитор еще не запущен")
+		return
+	}
+
+	var mem runtime.MemStats
+	runtime.ReadMemStats(&mem)
+	diag := b.monitor.Diagnostics()
+
+	endpoint := "не используется"
+	if diag.Endpoint != "" {
+		endpoint = html.EscapeString(diag.Endpoint)
+	}
+
+	lastGC := "не было"
+	if mem.LastGC > 0 {
+		lastGC = time.Since(time.Unix(0, int64(mem.LastGC))).Round(time.Second).String() + " назад"
+	}
+
+	text := fmt.Sprintf(`🩺 <b>Диагностика</b>
+
+Горутины: %d
+Heap: %.1f MB (объектов: %d)
+Получено от ОС: %.1f MB
+GC: %d циклов, пауза всего %s, последний %s
+
+Символов: %d
+Точек истории цен: %d
+Символов с объемом: %d
+WebSocket: %s
+
+Анализ: последний %s, средний %s, максимум %s (бюджет %s)`,
+		runtime.NumGoroutine(),
+		float64(mem.HeapAlloc)/1024/1024, mem.HeapObjects,
+		float64(mem.Sys)/1024/1024,
+		mem.NumGC, time.Duration(mem.PauseTotalNs).Round(time.Microsecond), lastGC,
+		diag.Symbols, diag.HistoryPoints, diag.VolumeSymbols, endpoint,
+		diag.AnalysisLast.Round(time.Millisecond), diag.AnalysisAvg.Round(time.Millisecond),
+		diag.AnalysisMax.Round(time.Millisecond), diag.AnalysisBudget)
+
+	b.sendMessage(message.Chat.ID, text)
+}
@@ -0,0 +1,61 @@
+package telegram
+
+import (
+	"fmt"
+	"html"
+	"strings"
+
+	"mexc-monitor/internal/logging"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	log "github.com/sirupsen/logrus"
+)
+
+const maxErrorMessageLength = 300
+
+func (b *Bot) SetErrorLog(errorLog *logging.RingHook) {
+	b.errorLog = errorLog
+}
+
+func (b *Bot) handleErrorsCommand(message *tgbotapi.Message, args string) {
+	if !b.requireAdmin(message) {
+		return
+	}
+
+	if b.errorLog == nil {
+		b.sendMessage(message.Chat.ID, "Журнал ошибок не включен")
+		return
+	}
+
+	entries := b.errorLog.Entries()
+	if limit, ok := parseLimit(args); !ok {
+		b.sendMessage(message.Chat.ID, "Неверное количество. Должно быть от 1 до 50.")
+		return
+	} else if len(entries) > limit {
+		entries = entries[len(entries)-limit:]
+	}
+
+	if len(entries) == 0 {
+		b.sendMessage(message.Chat.ID, "✅ Ошибок и предупреждений нет")
+		return
+	}
+
+	var response strings.Builder
+	response.WriteString(fmt.Sprintf("🧯 <b>Последние ошибки и предупреждения (%d)</b>\n\n", len(entries)))
+	for i := len(entries) - 1; i >= 0; i-- {
+		entry := entries[i]
+		icon := "⚠️"
+		if entry.Level <= log.ErrorLevel {
+			icon = "❌"
+		}
+
+		text := entry.Message
+		if runes := []rune(text); len(runes) > maxErrorMessageLength {
+			text = string(runes[:maxErrorMessageLength]) + "…"
+		}
+		response.WriteString(fmt.Sprintf("%s <code>%s</code> %s\n",
+			icon, entry.Time.Format("01-02 15:04:05"), html.EscapeString(text)))
+	}
+
+	b.sendLongMessage(message.Chat.ID, response.String())
+}
@@ -0,0 +1,128 @@
+package telegram
+
+import (
+	"fmt"
+	"html"
+	"strings"
+
+	"mexc-monitor/internal/database"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	eventMove         = "move"
+	eventSustained    = "sustained"
+	eventAcceleration = "acceleration"
+	eventCandles      = "candles"
+	eventLevel        = "level"
+	eventDelist       = "delist"
+)
+
+var eventTypes = []struct {
+	name        string
+	description string
+}{
+	{eventMove, "изменение цены (⚡ ALERT и сводки)"},
+	{eventSustained, "движение держится несколько интервалов (🚀 SUSTAINED)"},
+	{eventAcceleration, "ускорение движения (⚡ ACCELERATING)"},
+	{eventCandles, "свечи подряд в одну сторону (🕯 CANDLES)"},
+	{eventLevel, "достижение ценового уровня из /alert"},
+	{eventDelist, "снятие монеты из списка наблюдения с торгов"},
+}
+
+func parseEvents(value string) (string, error) {
+	value = strings.ToLower(strings.TrimSpace(value))
+	if value == "all" {
+		return "", nil
+	}
+
+	var events []string
+	for _, name := range strings.FieldsFunc(value, func(r rune) bool { return r == ',' || r == ' ' }) {
+		known := false
+		for _, event := range eventTypes {
+			if event.name == name {
+				known = true
+				break
+			}
+		}
+		if !known {
+			return "", fmt.Errorf("неизвестный тип алертов %q", name)
+		}
+		events = append(events, name)
+	}
+	if len(events) == 0 {
+		return "", fmt.Errorf("не указаны типы алертов")
+	}
+	return strings.Join(events, ","), nil
+}
+
+func wantsEvent(settings *database.UserSettings, event string) bool {
+	if settings.Events == "" {
+		return true
+	}
+	for _, name := range strings.Split(settings.Events, ",") {
+		if name == event {
+			return true
+		}
+	}
+	return false
+}
+
+func (b *Bot) subscribedTo(chatID int64, event string) bool {
+	settings, err := b.db.GetUserSettings(chatID)
+	if err != nil {
+		log.Errorf("Не удалось получить настройки пользователя %d: %v", chatID, err)
+		return true
+	}
+	return wantsEvent(settings, event)
+}
+
+func (b *Bot) handleSubscribeCommand(message *tgbotapi.Message, args string) {
+	if strings.TrimSpace(args) == "" {
+		b.showSubscriptions(message.Chat.ID)
+		return
+	}
+
+	events, err := parseEvents(args)
+	if err != nil {
+		b.sendMessage(message.Chat.ID, fmt.Sprintf("Неверные типы алертов: %s\nПример: /subscribe move,level или /subscribe all", html.EscapeString(err.Error())))
+		return
+	}
+
+	ok := b.updateUserSettings(message, func(settings *database.UserSettings) {
+		settings.Events = events
+	})
+	if !ok {
+		return
+	}
+
+	if events == "" {
+		b.sendMessage(message.Chat.ID, "Вы подписаны на все типы алертов")
+		return
+	}
+	b.sendMessage(message.Chat.ID, fmt.Sprintf("Вы подписаны на алерты: %s", events))
+}
+
+func (b *Bot) showSubscriptions(chatID int64) {
+	settings, err := b.db.GetUserSettings(chatID)
+	if err != nil {
+		log.Errorf("Failed to get user settings: %v", err)
+		b.sendMessage(chatID, "Ошибка получения текущих настроек")
+		return
+	}
+
+	var response strings.Builder
+	response.WriteString("🔔 <b>Типы алертов</b>\n\n")
+	for _, event := range eventTypes {
+		mark := "❌"
+		if wantsEvent(settings, event.name) {
+			mark = "✅"
+		}
+		response.WriteString(fmt.Sprintf("%s <code>%s</code> - %s\n", mark, event.name, event.description))
+	}
+	response.WriteString("\nИзменить: /subscribe move,level или /subscribe all")
+
+	b.sendMessage(chatID, response.String())
+}
@@ -0,0 +1,82 @@
+package telegram
+
+import "time"
+
+type Monitor interface {
+	TopVolumes(limit int) []SymbolVolume
+	Reset() int
+	TopMovers(limit int, gainers bool) []SymbolChange
+	Trace(symbol string, duration time.Duration)
+	Explain(symbol string) ConditionReport
+	RefreshSymbols() (added int, removed int, err error)
+	AnalyzeNow() int
+	PriceSeries(symbol string) []float64
+	CurrentPrice(symbol string) (float64, error)
+	Symbols() []string
+	FormatSymbol(symbol string) string
+	TradeURL(symbol string) string
+	Calm(multiplier float64, duration time.Duration) time.Time
+	CalmState() (multiplier float64, until time.Time)
+	Diagnostics() Diagnostics
+	CleanupPreview() CleanupPreview
+	QuoteVariants(base string) []string
+	ResetAnchors() (int, bool)
+	AnchorState() (enabled bool, since time.Time, symbols int)
+}
+
+type CleanupPreview struct {
+	Retention            time.Duration
+	PricePoints          int
+	ExpiredPricePoints   int
+	VolumeEntries        int
+	ExpiredVolumeEntries int
+}
+
+type Diagnostics struct {
+	Symbols        int
+	HistoryPoints  int
+	VolumeSymbols  int
+	Endpoint       string
+	AnalysisLast   time.Duration
+	AnalysisAvg    time.Duration
+	AnalysisMax    time.Duration
+	AnalysisBudget time.Duration
+}
+
+type SymbolVolume struct {
+	Symbol string
+	Volume int
+}
+
+type SymbolChange struct {
+	Symbol string
+	Change float64
+}
+
+type SkipReason int
+
+const (
+	SkipNone SkipReason = iota
+	SkipNoHistory
+	SkipStale
+	SkipPriceTooOld
+	SkipCooldown
+	SkipBlacklisted
+	SkipNoVolume
+)
+
+type ConditionReport struct {
+	Symbol          string
+	Skip            SkipReason
+	CurrentPrice    float64
+	PriceChange     float64
+	ChangeThreshold float64
+	Volume          int
+	MinVolume       int
+	Pinned          bool
+	ChangeOK        bool
+	VolumeOK        bool
+	Interval        time.Duration
+	BaselineAge     time.Duration
+	BaselineEMA     bool
+}
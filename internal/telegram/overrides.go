@@ -0,0 +1,100 @@
+package telegram
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	log "github.com/sirupsen/logrus"
+)
+
+const overrideUsage = "Использование: /set override &lt;символ&gt; cooldown &lt;секунды&gt;\nПример: /set override DOGEUSDT cooldown 600 (0 - использовать общий кулдаун)"
+
+func (b *Bot) handleSetOverride(message *tgbotapi.Message, args []string) {
+	if len(args) == 0 {
+		b.showOverrides(message.Chat.ID)
+		return
+	}
+
+	if len(args) != 3 || strings.ToLower(args[1]) != "cooldown" {
+		b.sendMessage(message.Chat.ID, overrideUsage)
+		return
+	}
+
+	symbol, ok := b.resolveSymbol(message.Chat.ID, args[0])
+	if !ok {
+		return
+	}
+	if !isValidSymbol(symbol) {
+		b.sendMessage(message.Chat.ID, "Неверный символ. Пример: DOGEUSDT")
+		return
+	}
+
+	seconds, err := strconv.Atoi(args[2])
+	if err != nil || seconds < 0 {
+		b.sendMessage(message.Chat.ID, "Неверный кулдаун. Должно быть целым числом секунд не меньше 0 (0 - использовать общий кулдаун).")
+		return
+	}
+
+	oldValue := "-"
+	if cooldowns, err := b.db.GetSymbolCooldowns(); err == nil {
+		if previous, ok := cooldowns[symbol]; ok {
+			oldValue = strconv.Itoa(previous)
+		}
+	}
+
+	if err := b.db.SetSymbolCooldown(symbol, seconds); err != nil {
+		log.Errorf("Failed to set cooldown override for %s: %v", symbol, err)
+		b.sendMessage(message.Chat.ID, "Ошибка сохранения настроек")
+		return
+	}
+
+	newValue := "-"
+	if seconds > 0 {
+		newValue = strconv.Itoa(seconds)
+		b.sendMessage(message.Chat.ID, fmt.Sprintf("Кулдаун для %s установлен на %s", symbol, formatDuration(time.Duration(seconds)*time.Second)))
+	} else {
+		b.sendMessage(message.Chat.ID, fmt.Sprintf("Кулдаун для %s сброшен, используется общий", symbol))
+	}
+	b.auditSettingChange(message.From, "override "+symbol+" cooldown", oldValue, newValue)
+}
+
+func (b *Bot) showOverrides(chatID int64) {
+	cooldowns, err := b.db.GetSymbolCooldowns()
+	if err != nil {
+		log.Errorf("Failed to get cooldown overrides: %v", err)
+		b.sendMessage(chatID, "Ошибка получения настроек")
+		return
+	}
+
+	if len(cooldowns) == 0 {
+		b.sendMessage(chatID, "Индивидуальных настроек монет нет\n\n"+overrideUsage)
+		return
+	}
+
+	symbols := make([]string, 0, len(cooldowns))
+	for symbol := range cooldowns {
+		symbols = append(symbols, symbol)
+	}
+	sort.Strings(symbols)
+
+	var response strings.Builder
+	response.WriteString("⚙️ Индивидуальные настройки монет:\n\n")
+	for _, symbol := range symbols {
+		response.WriteString(fmt.Sprintf("• %s: кулдаун %s\n", symbol, formatDuration(time.Duration(cooldowns[symbol])*time.Second)))
+	}
+	b.sendLongMessage(chatID, response.String())
+}
+
+func (b *Bot) symbolCooldown(symbol string) time.Duration {
+	cooldowns, err := b.db.GetSymbolCooldowns()
+	if err != nil {
+		log.Errorf("Failed to get cooldown overrides: %v", err)
+	} else if seconds, ok := cooldowns[symbol]; ok {
+		return time.Duration(seconds) * time.Second
+	}
+	return time.Duration(b.cfg.Monitoring.AlertCooldown) * time.Second
+}
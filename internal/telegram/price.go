@@ -0,0 +1,33 @@
+package telegram
+
+import (
+	"fmt"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	log "github.com/sirupsen/logrus"
+)
+
+func (b *Bot) handlePriceCommand(message *tgbotapi.Message, args string) {
+	if b.monitor == nil {
+		b.sendMessage(message.Chat.ID, "Монитор еще не запущен")
+		return
+	}
+
+	symbol, ok := b.resolveSymbol(message.Chat.ID, args)
+	if !ok {
+		return
+	}
+	if !isValidSymbol(symbol) {
+		b.sendMessage(message.Chat.ID, "Использование: /price &lt;символ&gt;\nПример: /price BTCUSDT")
+		return
+	}
+
+	price, err := b.monitor.CurrentPrice(symbol)
+	if err != nil {
+		log.Errorf("Не удалось получить цену %s: %v", symbol, err)
+		b.sendMessage(message.Chat.ID, fmt.Sprintf("Не удалось получить цену %s", symbol))
+		return
+	}
+
+	b.sendMessage(message.Chat.ID, fmt.Sprintf("💵 <b>%s</b>: %s", symbol, formatPrice(price)))
+}
@@ -0,0 +1,33 @@
+package telegram
+
+import "time"
+
+const commandWindow = time.Minute
+
+func (b *Bot) allowCommand(userID int64) bool {
+	limit := b.cfg.Telegram.CommandsPerMin
+	if limit <= 0 {
+		return true
+	}
+
+	now := time.Now()
+	cutoff := now.Add(-commandWindow)
+
+	b.commandsMu.Lock()
+	defer b.commandsMu.Unlock()
+
+	recent := b.commandTimes[userID][:0]
+	for _, t := range b.commandTimes[userID] {
+		if t.After(cutoff) {
+			recent = append(recent, t)
+		}
+	}
+
+	if len(recent) >= limit {
+		b.commandTimes[userID] = recent
+		return false
+	}
+
+	b.commandTimes[userID] = append(recent, now)
+	return true
+}
@@ -0,0 +1,106 @@
+package telegram
+
+import (
+	"fmt"
+	"html"
+	"strings"
+	"time"
+
+	"mexc-monitor/internal/database"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+type tradingSession struct {
+	start int
+	end   int
+}
+
+var namedSessions = map[string]tradingSession{
+	"asia": {start: 0, end: 9 * 60},
+	"eu":   {start: 7 * 60, end: 16 * 60},
+	"us":   {start: 13*60 + 30, end: 20 * 60},
+}
+
+func parseSessions(value string) ([]tradingSession, error) {
+	var sessions []tradingSession
+	for _, part := range strings.Split(value, ",") {
+		part = strings.ToLower(strings.TrimSpace(part))
+		if part == "" {
+			continue
+		}
+
+		if session, ok := namedSessions[part]; ok {
+			sessions = append(sessions, session)
+			continue
+		}
+
+		bounds := strings.Split(part, "-")
+		if len(bounds) != 2 {
+			return nil, fmt.Errorf("неизвестная сессия %q", part)
+		}
+		start, err := time.Parse("15:04", bounds[0])
+		if err != nil {
+			return nil, fmt.Errorf("неверное время %q", bounds[0])
+		}
+		end, err := time.Parse("15:04", bounds[1])
+		if err != nil {
+			return nil, fmt.Errorf("неверное время %q", bounds[1])
+		}
+		sessions = append(sessions, tradingSession{
+			start: start.Hour()*60 + start.Minute(),
+			end:   end.Hour()*60 + end.Minute(),
+		})
+	}
+	return sessions, nil
+}
+
+func (s tradingSession) contains(minute int) bool {
+	if s.start <= s.end {
+		return minute >= s.start && minute < s.end
+	}
+	return minute >= s.start || minute < s.end
+}
+
+func inTradingSession(settings *database.UserSettings, t time.Time) bool {
+	if settings.Sessions == "" {
+		return true
+	}
+
+	sessions, err := parseSessions(settings.Sessions)
+	if err != nil || len(sessions) == 0 {
+		return true
+	}
+
+	t = t.UTC()
+	minute := t.Hour()*60 + t.Minute()
+	for _, session := range sessions {
+		if session.contains(minute) {
+			return true
+		}
+	}
+	return false
+}
+
+func (b *Bot) handleSetSessions(message *tgbotapi.Message, valueStr string) {
+	value := strings.ToLower(valueStr)
+	if value == "off" {
+		value = ""
+	} else if _, err := parseSessions(value); err != nil {
+		b.sendMessage(message.Chat.ID, fmt.Sprintf("Неверные сессии: %s\nПример: /set sessions eu,us или /set sessions 08:00-12:00,14:00-18:00 (UTC)", html.EscapeString(err.Error())))
+		return
+	}
+
+	ok := b.updateUserSettings(message, func(settings *database.UserSettings) {
+		settings.Sessions = value
+	})
+	if !ok {
+		return
+	}
+
+	if value == "" {
+		b.sendMessage(message.Chat.ID, "Торговые сессии отключены, алерты приходят круглосуточно")
+		return
+	}
+	b.sendMessage(message.Chat.ID, fmt.Sprintf("Алерты будут приходить только в сессии (UTC): %s", value))
+}
@@ -0,0 +1,135 @@
+package telegram
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"mexc-monitor/internal/database"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	log "github.com/sirupsen/logrus"
+)
+
+func encodeSettings(settings *database.Settings) (string, error) {
+	data, err := json.Marshal(settings)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+func decodeSettings(code string) (*database.Settings, error) {
+	data, err := base64.RawURLEncoding.DecodeString(strings.TrimSpace(code))
+	if err != nil {
+		return nil, errors.New("код поврежден")
+	}
+
+	var settings database.Settings
+	if err := json.Unmarshal(data, &settings); err != nil {
+		return nil, errors.New("код поврежден")
+	}
+
+	switch {
+	case settings.TimeInterval <= 0:
+		return nil, errors.New("интервал времени должен быть положительным")
+	case settings.PriceChange <= 0:
+		return nil, errors.New("порог изменения цены должен быть положительным")
+	case settings.MinVolume <= 0:
+		return nil, errors.New("минимальный объем должен быть положительным")
+	case settings.MinVolumeUp < 0 || settings.MinVolumeDown < 0:
+		return nil, errors.New("минимальный объем по направлению не может быть отрицательным")
+	case settings.Retention <= 0:
+		return nil, errors.New("время хранения истории должно быть положительным")
+	}
+
+	return &settings, nil
+}
+
+func (b *Bot) handleShareCommand(message *tgbotapi.Message) {
+	settings, err := b.db.GetSettings()
+	if err != nil {
+		log.Errorf("Failed to get settings: %v", err)
+		b.sendMessage(message.Chat.ID, "Ошибка получения настроек")
+		return
+	}
+
+	code, err := encodeSettings(settings)
+	if err != nil {
+		log.Errorf("Failed to encode settings: %v", err)
+		b.sendMessage(message.Chat.ID, "Ошибка кодирования настроек")
+		return
+	}
+
+	b.sendMessage(message.Chat.ID, fmt.Sprintf("📤 Код текущих настроек:\n\n<code>%s</code>\n\nПрименить: /apply &lt;код&gt;", code))
+}
+
+type settingChange struct {
+	key      string
+	oldValue string
+	newValue string
+}
+
+func settingsChanges(old, updated *database.Settings) []settingChange {
+	fields := []settingChange{
+		{"time", strconv.Itoa(old.TimeInterval), strconv.Itoa(updated.TimeInterval)},
+		{"change", formatPrice(old.PriceChange), formatPrice(updated.PriceChange)},
+		{"volume", strconv.Itoa(old.MinVolume), strconv.Itoa(updated.MinVolume)},
+		{"volume_up", strconv.Itoa(old.MinVolumeUp), strconv.Itoa(updated.MinVolumeUp)},
+		{"volume_down", strconv.Itoa(old.MinVolumeDown), strconv.Itoa(updated.MinVolumeDown)},
+		{"retention", strconv.Itoa(old.Retention), strconv.Itoa(updated.Retention)},
+	}
+
+	var changes []settingChange
+	for _, field := range fields {
+		if field.oldValue != field.newValue {
+			changes = append(changes, field)
+		}
+	}
+	return changes
+}
+
+func (b *Bot) handleApplyCommand(message *tgbotapi.Message, args string) {
+	if !b.requireAdmin(message) {
+		return
+	}
+
+	if strings.TrimSpace(args) == "" {
+		b.sendMessage(message.Chat.ID, "Использование: /apply &lt;код&gt;\nКод можно получить командой /share")
+		return
+	}
+
+	settings, err := decodeSettings(args)
+	if err != nil {
+		b.sendMessage(message.Chat.ID, fmt.Sprintf("Неверный код настроек: %s", err))
+		return
+	}
+
+	current, err := b.db.GetSettings()
+	if err != nil {
+		log.Errorf("Failed to get settings: %v", err)
+		b.sendMessage(message.Chat.ID, "Ошибка получения настроек")
+		return
+	}
+
+	if err := b.db.UpdateSettings(settings); err != nil {
+		log.Errorf("Failed to update settings: %v", err)
+		b.sendMessage(message.Chat.ID, "Ошибка сохранения настроек")
+		return
+	}
+
+	for _, change := range settingsChanges(current, settings) {
+		b.auditSettingChange(message.From, change.key, change.oldValue, change.newValue)
+	}
+
+	log.Infof("Пользователь %d применил настройки из кода", message.From.ID)
+	b.sendMessage(message.Chat.ID, fmt.Sprintf("✅ Настройки применены:\n\n"+
+		"⏱ Интервал времени: %d секунд\n"+
+		"📈 Изменение цены: %.2f%%\n"+
+		"💰 Минимальный объем: $%d\n"+
+		"🗄 Хранение истории: %d минут",
+		settings.TimeInterval, settings.PriceChange, settings.MinVolume, settings.Retention))
+}
@@ -0,0 +1,57 @@
+package telegram
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	summaryAlertKey = "summary"
+	snoozeCallback  = "snooze"
+	snoozeDuration  = 30 * time.Minute
+)
+
+func snoozeKeyboard(symbol string) tgbotapi.InlineKeyboardMarkup {
+	return tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("🔕 Отложить 30м", snoozeCallback+":"+symbol),
+		),
+	)
+}
+
+func (b *Bot) handleCallback(callback *tgbotapi.CallbackQuery) {
+	action, symbol, ok := strings.Cut(callback.Data, ":")
+	if !ok || action != snoozeCallback || callback.Message == nil {
+		b.answerCallback(callback, "Неизвестное действие")
+		return
+	}
+
+	chatID := callback.Message.Chat.ID
+	if err := b.db.MuteSymbol(chatID, symbol, snoozeDuration); err != nil {
+		log.Errorf("Не удалось отложить %s для пользователя %d: %v", symbol, chatID, err)
+		b.answerCallback(callback, "Ошибка, попробуйте еще раз")
+		return
+	}
+
+	log.Infof("Пользователь %d отложил алерты по %s на %s", chatID, symbol, snoozeDuration)
+	b.answerCallback(callback, fmt.Sprintf("🔕 Алерты по %s отложены на 30 минут", symbol))
+}
+
+func (b *Bot) answerCallback(callback *tgbotapi.CallbackQuery, text string) {
+	if _, err := b.sender.Request(tgbotapi.NewCallback(callback.ID, text)); err != nil {
+		log.Warnf("Не удалось ответить на нажатие кнопки: %v", err)
+	}
+}
+
+func (b *Bot) isMuted(userID int64, symbol string) bool {
+	muted, err := b.db.IsMuted(userID, symbol)
+	if err != nil {
+		log.Errorf("Не удалось проверить отложенные алерты пользователя %d: %v", userID, err)
+		return false
+	}
+	return muted
+}
@@ -0,0 +1,53 @@
+package telegram
+
+import (
+	"fmt"
+	"html"
+	"strings"
+)
+
+func (b *Bot) symbolCandidates(symbol string) []string {
+	if b.monitor == nil || !isValidSymbol(symbol) {
+		return []string{symbol}
+	}
+
+	for _, known := range b.monitor.Symbols() {
+		if known == symbol {
+			return []string{symbol}
+		}
+	}
+
+	if variants := b.monitor.QuoteVariants(symbol); len(variants) > 0 {
+		return variants
+	}
+	return []string{symbol}
+}
+
+func (b *Bot) resolveSymbol(chatID int64, input string) (string, bool) {
+	symbol := strings.ToUpper(strings.TrimSpace(input))
+
+	candidates := b.symbolCandidates(symbol)
+	if len(candidates) == 1 {
+		return candidates[0], true
+	}
+
+	b.sendMessage(chatID, fmt.Sprintf("❓ %s торгуется в нескольких парах, уточните: %s",
+		html.EscapeString(symbol), strings.Join(candidates, ", ")))
+	return "", false
+}
+
+func (b *Bot) resolveSymbols(symbols []string) (resolved []string, ambiguous []string) {
+	seen := make(map[string]bool)
+	for _, symbol := range symbols {
+		candidates := b.symbolCandidates(symbol)
+		if len(candidates) > 1 {
+			ambiguous = append(ambiguous, fmt.Sprintf("%s (уточните: %s)", symbol, strings.Join(candidates, ", ")))
+			continue
+		}
+		if !seen[candidates[0]] {
+			seen[candidates[0]] = true
+			resolved = append(resolved, candidates[0])
+		}
+	}
+	return resolved, ambiguous
+}
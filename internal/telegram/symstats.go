@@ -0,0 +1,83 @@
+package telegram
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"mexc-monitor/internal/database"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	log "github.com/sirupsen/logrus"
+)
+
+func (b *Bot) handleSymStatsCommand(message *tgbotapi.Message, args string) {
+	symbol, ok := b.resolveSymbol(message.Chat.ID, args)
+	if !ok {
+		return
+	}
+	if !isValidSymbol(symbol) {
+		b.sendMessage(message.Chat.ID, "Использование: /symstats &lt;символ&gt;\nПример: /symstats BTCUSDT")
+		return
+	}
+
+	alerts, err := b.db.QueryAlerts(database.AlertFilter{Symbol: symbol})
+	if err != nil {
+		log.Errorf("Failed to query alerts for %s: %v", symbol, err)
+		b.sendMessage(message.Chat.ID, "Ошибка получения истории алертов")
+		return
+	}
+
+	now := time.Now()
+	lastDay, up, down := 0, 0, 0
+	for _, alert := range alerts {
+		if now.Sub(alert.CreatedAt) < 24*time.Hour {
+			lastDay++
+		}
+		if alert.Direction == "up" {
+			up++
+		} else {
+			down++
+		}
+	}
+
+	var response strings.Builder
+	response.WriteString(fmt.Sprintf("📊 <b>Статистика алертов %s</b>\n\n", symbol))
+	response.WriteString(fmt.Sprintf("Всего алертов: %d (📈 %d / 📉 %d)\n", len(alerts), up, down))
+	response.WriteString(fmt.Sprintf("За 24 часа: %d\n", lastDay))
+	if len(alerts) > 0 {
+		last := alerts[0]
+		response.WriteString(fmt.Sprintf("Последний: %s UTC, %+.2f%% (%s назад)\n",
+			last.CreatedAt.UTC().Format("2006-01-02 15:04"), last.Change, formatDuration(now.Sub(last.CreatedAt))))
+	} else {
+		response.WriteString("Последний: нет\n")
+	}
+
+	cooldownStatus := "не настроен"
+	if cooldown := b.symbolCooldown(symbol); cooldown > 0 {
+		lastAlert, ok, err := b.db.GetLastAlert(symbol)
+		switch {
+		case err != nil:
+			log.Errorf("Failed to get last alert for %s: %v", symbol, err)
+			cooldownStatus = "ошибка проверки"
+		case ok && now.Sub(lastAlert) < cooldown:
+			cooldownStatus = fmt.Sprintf("активен еще %s", formatDuration(lastAlert.Add(cooldown).Sub(now)))
+		default:
+			cooldownStatus = "неактивен"
+		}
+	}
+	response.WriteString(fmt.Sprintf("Кулдаун: %s\n", cooldownStatus))
+
+	blacklistStatus := "нет"
+	if expiresAt, blacklisted, err := b.db.GetBlacklistExpiry(symbol); err != nil {
+		log.Errorf("Failed to check blacklist for %s: %v", symbol, err)
+		blacklistStatus = "ошибка проверки"
+	} else if blacklisted && expiresAt.IsZero() {
+		blacklistStatus = "да, постоянно"
+	} else if blacklisted {
+		blacklistStatus = fmt.Sprintf("да, истекает через %s", formatDuration(time.Until(expiresAt)))
+	}
+	response.WriteString(fmt.Sprintf("Черный список: %s", blacklistStatus))
+
+	b.sendMessage(message.Chat.ID, response.String())
+}
@@ -0,0 +1,25 @@
+package telegram
+
+import (
+	"fmt"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	log "github.com/sirupsen/logrus"
+)
+
+func (b *Bot) handleReloadUsersCommand(message *tgbotapi.Message) {
+	if !b.requireAdmin(message) {
+		return
+	}
+
+	before := len(b.users())
+	count, err := b.loadUsers()
+	if err != nil {
+		log.Errorf("Не удалось перечитать пользователей: %v", err)
+		b.sendMessage(message.Chat.ID, "Ошибка загрузки пользователей из базы данных")
+		return
+	}
+
+	log.Infof("Пользователь %d перечитал список пользователей: %d -> %d", message.From.ID, before, count)
+	b.sendMessage(message.Chat.ID, fmt.Sprintf("👥 Список пользователей перечитан из базы данных: %d (было %d)", count, before))
+}
@@ -0,0 +1,208 @@
+package telegram
+
+import (
+	"fmt"
+	"html"
+	"io"
+	"net/http"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	log "github.com/sirupsen/logrus"
+)
+
+func (b *Bot) handleWatchCommand(message *tgbotapi.Message, args string) {
+	parts := strings.Fields(args)
+	if len(parts) == 0 {
+		b.showWatchlist(message)
+		return
+	}
+
+	symbol, ok := b.resolveSymbol(message.Chat.ID, parts[0])
+	if !ok {
+		return
+	}
+	if len(parts) != 1 || !isValidSymbol(symbol) {
+		b.sendMessage(message.Chat.ID, "Использование: /watch &lt;символ&gt;\nПример: /watch BTCUSDT")
+		return
+	}
+
+	added, err := b.db.AddToWatchlist(message.Chat.ID, symbol)
+	if err != nil {
+		log.Errorf("Failed to add %s to watchlist: %v", symbol, err)
+		b.sendMessage(message.Chat.ID, "Ошибка добавления в список наблюдения")
+		return
+	}
+
+	if !added {
+		b.sendMessage(message.Chat.ID, fmt.Sprintf("%s уже в вашем списке наблюдения", symbol))
+		return
+	}
+	b.sendMessage(message.Chat.ID, fmt.Sprintf("👀 %s добавлен в список наблюдения", symbol))
+}
+
+func (b *Bot) handleUnwatchCommand(message *tgbotapi.Message, args string) {
+	symbol, ok := b.resolveSymbol(message.Chat.ID, args)
+	if !ok {
+		return
+	}
+	if !isValidSymbol(symbol) {
+		b.sendMessage(message.Chat.ID, "Использование: /unwatch &lt;символ&gt;\nПример: /unwatch BTCUSDT")
+		return
+	}
+
+	removed, err := b.db.RemoveFromWatchlist(message.Chat.ID, symbol)
+	if err != nil {
+		log.Errorf("Failed to remove %s from watchlist: %v", symbol, err)
+		b.sendMessage(message.Chat.ID, "Ошибка удаления из списка наблюдения")
+		return
+	}
+
+	if !removed {
+		b.sendMessage(message.Chat.ID, fmt.Sprintf("%s нет в вашем списке наблюдения", symbol))
+		return
+	}
+	b.sendMessage(message.Chat.ID, fmt.Sprintf("%s убран из списка наблюдения", symbol))
+}
+
+const (
+	maxImportFileSize = 64 * 1024
+	importCommand     = "/import_watchlist"
+)
+
+func isImportCaption(message *tgbotapi.Message) bool {
+	return message.Document != nil && strings.HasPrefix(strings.TrimSpace(message.Caption), importCommand)
+}
+
+func (b *Bot) handleImportWatchlistCommand(message *tgbotapi.Message, args string) {
+	input := args
+	document := message.Document
+	if document == nil && message.ReplyToMessage != nil {
+		document = message.ReplyToMessage.Document
+	}
+	if document != nil {
+		content, err := b.downloadDocument(document)
+		if err != nil {
+			log.Errorf("Failed to download watchlist file: %v", err)
+			b.sendMessage(message.Chat.ID, "Не удалось прочитать файл со списком")
+			return
+		}
+		input += "\n" + content
+	}
+
+	symbols, invalid := parseSymbolList(input)
+	if len(symbols) == 0 && len(invalid) == 0 {
+		b.sendMessage(message.Chat.ID, "Использование: /import_watchlist &lt;символ&gt;,&lt;символ&gt;...\n"+
+			"Символы можно перечислить через запятую или с новой строки, либо прислать текстовый файл с подписью /import_watchlist")
+		return
+	}
+
+	if b.monitor != nil {
+		active := make(map[string]bool)
+		for _, symbol := range b.monitor.Symbols() {
+			active[symbol] = true
+		}
+
+		var tracked []string
+		for _, symbol := range symbols {
+			if active[symbol] {
+				tracked = append(tracked, symbol)
+			} else {
+				invalid = append(invalid, symbol)
+			}
+		}
+		symbols = tracked
+	}
+
+	added := 0
+	if len(symbols) > 0 {
+		var err error
+		added, err = b.db.AddManyToWatchlist(message.Chat.ID, symbols)
+		if err != nil {
+			log.Errorf("Failed to import watchlist: %v", err)
+			b.sendMessage(message.Chat.ID, "Ошибка импорта списка наблюдения")
+			return
+		}
+	}
+
+	log.Infof("Пользователь %d импортировал список наблюдения: %d добавлено, %d уже было, %d отклонено",
+		message.Chat.ID, added, len(symbols)-added, len(invalid))
+
+	var response strings.Builder
+	response.WriteString(fmt.Sprintf("📥 Импорт списка наблюдения\n\nДобавлено: %d\nУже в списке: %d\n", added, len(symbols)-added))
+	if len(invalid) > 0 {
+		response.WriteString(fmt.Sprintf("\n❌ Не найдены среди отслеживаемых пар (%d):\n", len(invalid)))
+		for _, symbol := range invalid {
+			response.WriteString(fmt.Sprintf("• %s\n", html.EscapeString(symbol)))
+		}
+	}
+	b.sendLongMessage(message.Chat.ID, response.String())
+}
+
+func (b *Bot) downloadDocument(document *tgbotapi.Document) (string, error) {
+	if document.FileSize > maxImportFileSize {
+		return "", fmt.Errorf("file is too large: %d bytes", document.FileSize)
+	}
+
+	url, err := b.sender.GetFileDirectURL(document.FileID)
+	if err != nil {
+		return "", err
+	}
+
+	resp, err := http.Get(url)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxImportFileSize))
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+func (b *Bot) showWatchlist(message *tgbotapi.Message) {
+	symbols, err := b.db.GetWatchlist(message.Chat.ID)
+	if err != nil {
+		log.Errorf("Failed to get watchlist: %v", err)
+		b.sendMessage(message.Chat.ID, "Ошибка получения списка наблюдения")
+		return
+	}
+
+	if len(symbols) == 0 {
+		b.sendMessage(message.Chat.ID, "Список наблюдения пуст. Добавьте монету: /watch BTCUSDT")
+		return
+	}
+
+	var response strings.Builder
+	response.WriteString(fmt.Sprintf("👀 <b>Список наблюдения (%d):</b>\n\n", len(symbols)))
+	for _, symbol := range symbols {
+		price := "нет данных"
+		if b.monitor != nil {
+			if series := b.monitor.PriceSeries(symbol); len(series) > 0 {
+				price = formatPrice(series[len(series)-1])
+			}
+		}
+		response.WriteString(fmt.Sprintf("• %s - %s\n", symbol, price))
+	}
+	b.sendLongMessage(message.Chat.ID, response.String())
+}
+
+func (b *Bot) SendDelistNotice(chatID int64, symbol string) error {
+	if !b.subscribedTo(chatID, eventDelist) {
+		return nil
+	}
+
+	text := fmt.Sprintf("⚠️ <b>%s</b> больше не торгуется на MEXC и удален из вашего списка наблюдения",
+		html.EscapeString(b.displaySymbol(symbol)))
+
+	msg := tgbotapi.NewMessage(chatID, text)
+	msg.ParseMode = "HTML"
+
+	return b.send(chatID, msg)
+}
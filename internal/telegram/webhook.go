@@ -0,0 +1,82 @@
+package telegram
+
+import (
+	"crypto/subtle"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	log "github.com/sirupsen/logrus"
+)
+
+const webhookSecretHeader = "X-Telegram-Bot-Api-Secret-Token"
+
+var webhookSecretPattern = regexp.MustCompile(`^[A-Za-z0-9_-]{1,256}$`)
+
+func (b *Bot) listenWebhook() (tgbotapi.UpdatesChannel, error) {
+	webhookURL := b.cfg.Telegram.WebhookURL
+	if webhookURL == "" {
+		return nil, fmt.Errorf("не задан telegram.webhook_url для режима webhook")
+	}
+
+	secret := b.cfg.Telegram.WebhookSecret
+	if secret == "" {
+		return nil, fmt.Errorf("не задан telegram.webhook_secret для режима webhook")
+	}
+	if !webhookSecretPattern.MatchString(secret) {
+		return nil, fmt.Errorf("telegram.webhook_secret должен содержать 1-256 символов A-Z, a-z, 0-9, _ или -")
+	}
+
+	parsed, err := url.Parse(webhookURL)
+	if err != nil {
+		return nil, fmt.Errorf("неверный telegram.webhook_url: %v", err)
+	}
+
+	path := parsed.Path
+	if path == "" {
+		path = "/"
+	}
+
+	params := tgbotapi.Params{
+		"url":          webhookURL,
+		"secret_token": secret,
+	}
+	if _, err := b.api.MakeRequest("setWebhook", params); err != nil {
+		return nil, fmt.Errorf("ошибка регистрации webhook: %v", err)
+	}
+
+	updates := make(chan tgbotapi.Update, b.api.Buffer)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc(path, func(w http.ResponseWriter, r *http.Request) {
+		if subtle.ConstantTimeCompare([]byte(r.Header.Get(webhookSecretHeader)), []byte(secret)) != 1 {
+			log.Warnf("Запрос webhook с неверным секретом от %s", r.RemoteAddr)
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+
+		update, err := b.api.HandleUpdate(r)
+		if err != nil {
+			log.Warnf("Некорректный запрос webhook: %v", err)
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		updates <- *update
+	})
+
+	b.webhookServer = &http.Server{
+		Addr:    b.cfg.Telegram.WebhookListen,
+		Handler: mux,
+	}
+
+	go func() {
+		log.Infof("Webhook сервер слушает %s%s", b.cfg.Telegram.WebhookListen, path)
+		if err := b.webhookServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			log.Errorf("Ошибка webhook сервера: %v", err)
+		}
+	}()
+
+	return updates, nil
+}
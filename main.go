@@ -2,12 +2,16 @@ package main
 
 import (
 	"context"
+	"io"
 	"os"
 	"os/signal"
+	"sync"
 	"syscall"
+	"time"
 
 	"mexc-monitor/internal/config"
 	"mexc-monitor/internal/database"
+	"mexc-monitor/internal/logging"
 	"mexc-monitor/internal/monitor"
 	"mexc-monitor/internal/telegram"
 
@@ -20,7 +24,7 @@ func main() {
 		log.Fatalf("Failed to load config: %v", err)
 	}
 
-	setupLogging(cfg)
+	errorLog, dedupLog := setupLogging(cfg)
 
 	log.Info("Starting MEXC Monitor...")
 
@@ -30,7 +34,7 @@ func main() {
 	}
 	defer db.Close()
 
-	bot, err := telegram.NewBot(cfg.Telegram.BotToken, db)
+	bot, err := telegram.NewBot(cfg, db)
 	if err != nil {
 		log.Fatalf("Failed to initialize Telegram bot: %v", err)
 	}
@@ -39,17 +43,27 @@ func main() {
 	if err != nil {
 		log.Fatalf("Failed to initialize monitor: %v", err)
 	}
+	bot.SetMonitor(mon)
+	if errorLog != nil {
+		bot.SetErrorLog(errorLog)
+	}
 
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
+	var wg sync.WaitGroup
+
+	wg.Add(1)
 	go func() {
+		defer wg.Done()
 		if err := mon.Start(ctx); err != nil {
 			log.Errorf("Monitor error: %v", err)
 		}
 	}()
 
+	wg.Add(1)
 	go func() {
+		defer wg.Done()
 		if err := bot.Start(); err != nil {
 			log.Errorf("Telegram bot error: %v", err)
 		}
@@ -61,28 +75,51 @@ func main() {
 
 	log.Info("Shutting down...")
 	cancel()
+	bot.Stop()
+	wg.Wait()
+	log.Info("Shutdown complete")
+
+	if dedupLog != nil {
+		dedupLog.Close()
+	}
 }
 
-func setupLogging(cfg *config.Config) {
+func setupLogging(cfg *config.Config) (*logging.RingHook, *logging.DedupHook) {
 	level, err := log.ParseLevel(cfg.Logging.Level)
 	if err != nil {
 		level = log.InfoLevel
 	}
 	log.SetLevel(level)
 
+	var errorLog *logging.RingHook
+	if cfg.Logging.ErrorBuffer > 0 {
+		errorLog = logging.NewRingHook(cfg.Logging.ErrorBuffer)
+		log.AddHook(errorLog)
+	}
+
 	if err := os.MkdirAll("logs", 0755); err != nil {
 		log.Warnf("Failed to create logs directory: %v", err)
-		return
+		return errorLog, nil
 	}
 
 	file, err := os.OpenFile(cfg.Logging.File, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0666)
 	if err != nil {
 		log.Warnf("Failed to open log file: %v", err)
-		return
+		return errorLog, nil
 	}
 
-	log.SetOutput(file)
-	log.SetFormatter(&log.TextFormatter{
+	formatter := &log.TextFormatter{
 		FullTimestamp: true,
-	})
+	}
+	log.SetFormatter(formatter)
+
+	if cfg.Logging.RepeatWindow <= 0 {
+		log.SetOutput(file)
+		return errorLog, nil
+	}
+
+	dedupLog := logging.NewDedupHook(file, formatter, time.Duration(cfg.Logging.RepeatWindow)*time.Second)
+	log.AddHook(dedupLog)
+	log.SetOutput(io.Discard)
+	return errorLog, dedupLog
 }